@@ -3,13 +3,14 @@ package callbacks
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/utils"
 )
 
 var (
-	createClauses = []string{"INSERT", "VALUES", "ON CONFLICT"}
-	queryClauses  = []string{"SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR"}
-	updateClauses = []string{"UPDATE", "SET", "WHERE"}
-	deleteClauses = []string{"DELETE", "FROM", "WHERE"}
+	createClauses = []string{"WITH", "INSERT", "VALUES", "ON CONFLICT"}
+	queryClauses  = []string{"WITH", "SELECT", "FROM", "WHERE", "GROUP BY", "ORDER BY", "LIMIT", "FOR"}
+	updateClauses = []string{"WITH", "UPDATE", "SET", "WHERE"}
+	deleteClauses = []string{"WITH", "DELETE", "FROM", "WHERE"}
 )
 
 type Config struct {
@@ -18,6 +19,12 @@ type Config struct {
 	QueryClauses         []string
 	UpdateClauses        []string
 	DeleteClauses        []string
+	// UpdateJoinStyle how joined tables are written in updates, defaults to JoinStyleFrom if UpdateClauses contains
+	// FROM, otherwise JoinStyleJoin
+	UpdateJoinStyle JoinStyle
+	// DeleteJoinStyle how joined tables are written in deletes, defaults to JoinStyleJoin if UpdateJoinStyle is
+	// JoinStyleJoin, otherwise JoinStyleSubQuery
+	DeleteJoinStyle JoinStyle
 }
 
 func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
@@ -38,6 +45,33 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 		config.UpdateClauses = updateClauses
 	}
 
+	// common table expressions are written ahead of the statement, even for dialects that customize their clauses
+	config.CreateClauses = withLeadingClause(config.CreateClauses, "WITH")
+	config.QueryClauses = withLeadingClause(config.QueryClauses, "WITH")
+	config.UpdateClauses = withLeadingClause(config.UpdateClauses, "WITH")
+	config.DeleteClauses = withLeadingClause(config.DeleteClauses, "WITH")
+
+	if config.UpdateJoinStyle == "" {
+		if utils.Contains(config.UpdateClauses, "FROM") {
+			config.UpdateJoinStyle = JoinStyleFrom
+		} else {
+			config.UpdateJoinStyle = JoinStyleJoin
+		}
+	}
+	if config.DeleteJoinStyle == "" {
+		if config.UpdateJoinStyle == JoinStyleJoin {
+			config.DeleteJoinStyle = JoinStyleJoin
+		} else {
+			config.DeleteJoinStyle = JoinStyleSubQuery
+		}
+	}
+	if config.UpdateJoinStyle == JoinStyleFrom && !utils.Contains(config.UpdateClauses, "FROM") {
+		config.UpdateClauses = withClauseAfter(config.UpdateClauses, "FROM", "SET")
+	}
+	if config.DeleteJoinStyle == JoinStyleFrom && !utils.Contains(config.DeleteClauses, "USING") {
+		config.DeleteClauses = withClauseAfter(config.DeleteClauses, "USING", "FROM")
+	}
+
 	createCallback := db.Callback().Create()
 	createCallback.Match(enableTransaction).Register("gorm:begin_transaction", BeginTransaction)
 	createCallback.Register("gorm:before_create", BeforeCreate)
@@ -82,3 +116,23 @@ func RegisterDefaultCallbacks(db *gorm.DB, config *Config) {
 	rawCallback.Register("gorm:raw", RawExec)
 	rawCallback.Clauses = config.QueryClauses
 }
+
+// withLeadingClause prepends name to clauses if it is missing
+func withLeadingClause(clauses []string, name string) []string {
+	if utils.Contains(clauses, name) {
+		return clauses
+	}
+	return append([]string{name}, clauses...)
+}
+
+// withClauseAfter inserts name into clauses after the clause after
+func withClauseAfter(clauses []string, name, after string) []string {
+	result := make([]string, 0, len(clauses)+1)
+	for _, c := range clauses {
+		result = append(result, c)
+		if c == after {
+			result = append(result, name)
+		}
+	}
+	return result
+}
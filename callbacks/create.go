@@ -42,14 +42,23 @@ func Create(config *Config) func(db *gorm.DB) {
 			return
 		}
 
+		// INSERT ... SELECT, rows are not created from the dest, so there are no values to fill back
+		var fromQuery bool
+		if c, ok := db.Statement.Clauses["VALUES"]; ok {
+			if values, ok := c.Expression.(clause.Values); ok {
+				fromQuery = values.Query != nil
+			}
+		}
+
 		if db.Statement.Schema != nil {
 			if !db.Statement.Unscoped {
 				for _, c := range db.Statement.Schema.CreateClauses {
 					db.Statement.AddClause(c)
 				}
+				defer checkVersionLock(db)
 			}
 
-			if supportReturning && len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 {
+			if supportReturning && !fromQuery && len(db.Statement.Schema.FieldsWithDefaultDBValue) > 0 {
 				if _, ok := db.Statement.Clauses["RETURNING"]; !ok {
 					fromColumns := make([]clause.Column, 0, len(db.Statement.Schema.FieldsWithDefaultDBValue))
 					for _, field := range db.Statement.Schema.FieldsWithDefaultDBValue {
@@ -67,7 +76,9 @@ func Create(config *Config) func(db *gorm.DB) {
 		if db.Statement.SQL.Len() == 0 {
 			db.Statement.SQL.Grow(180)
 			db.Statement.AddClauseIfNotExists(clause.Insert{})
-			db.Statement.AddClause(ConvertToCreateValues(db.Statement))
+			if !fromQuery {
+				db.Statement.AddClause(ConvertToCreateValues(db.Statement))
+			}
 
 			db.Statement.Build(db.Statement.BuildClauses...)
 		}
@@ -120,7 +131,7 @@ func Create(config *Config) func(db *gorm.DB) {
 			db.Statement.Result.RowsAffected = db.RowsAffected
 		}
 
-		if db.RowsAffected == 0 {
+		if db.RowsAffected == 0 || fromQuery {
 			return
 		}
 
@@ -239,6 +250,8 @@ func AfterCreate(db *gorm.DB) {
 			return called
 		})
 	}
+
+	registerTransactionHooks(db)
 }
 
 // ConvertToCreateValues convert to create values
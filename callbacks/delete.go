@@ -118,6 +118,14 @@ func Delete(config *Config) func(db *gorm.DB) {
 			return
 		}
 
+		if db.Statement.SQL.Len() == 0 && len(db.Statement.Joins) != 0 {
+			// joins may become conditions, check the conditions given by users
+			if !hasPrimaryValues(db.Statement) {
+				checkMissingWhereConditions(db)
+			}
+			deleteJoins(db, config.DeleteJoinStyle)
+		}
+
 		if db.Statement.Schema != nil {
 			for _, c := range db.Statement.Schema.DeleteClauses {
 				db.Statement.AddClause(c)
@@ -182,6 +190,23 @@ func Delete(config *Config) func(db *gorm.DB) {
 	}
 }
 
+// hasPrimaryValues returns true if the deleting values have primary values, which are used as conditions
+func hasPrimaryValues(stmt *gorm.Statement) bool {
+	if stmt.Schema == nil {
+		return false
+	}
+
+	if _, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields); len(queryValues) > 0 {
+		return true
+	}
+
+	if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
+		_, queryValues := schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(stmt.Model), stmt.Schema.PrimaryFields)
+		return len(queryValues) > 0
+	}
+	return false
+}
+
 func AfterDelete(db *gorm.DB) {
 	if db.Error == nil && db.Statement.Schema != nil && !db.Statement.SkipHooks && db.Statement.Schema.AfterDelete {
 		callMethod(db, func(value interface{}, tx *gorm.DB) bool {
@@ -192,4 +217,6 @@ func AfterDelete(db *gorm.DB) {
 			return false
 		})
 	}
+
+	registerTransactionHooks(db)
 }
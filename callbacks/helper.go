@@ -122,6 +122,16 @@ func checkMissingWhereConditions(db *gorm.DB) {
 	}
 }
 
+// checkVersionLock checks the optimistic lock of versions after statements are executed
+func checkVersionLock(db *gorm.DB) {
+	if c, ok := db.Statement.Clauses["version_lock"]; ok {
+		delete(db.Statement.Clauses, "version_lock")
+		if lock, ok := c.Expression.(gorm.VersionLock); ok {
+			lock.Check(db)
+		}
+	}
+}
+
 type visitMap = map[reflect.Value]bool
 
 // Check if circular values, return true if loaded
@@ -37,3 +37,11 @@ type AfterDeleteInterface interface {
 type AfterFindInterface interface {
 	AfterFind(*gorm.DB) error
 }
+
+type AfterCommitInterface interface {
+	AfterCommit(*gorm.DB) error
+}
+
+type AfterRollbackInterface interface {
+	AfterRollback(*gorm.DB) error
+}
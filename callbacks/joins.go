@@ -0,0 +1,181 @@
+package callbacks
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JoinStyle how joined tables are written in UPDATE and DELETE statements
+type JoinStyle string
+
+const (
+	// JoinStyleFrom joined tables are written as FROM tables of updates and USING tables of deletes, join conditions
+	// are moved to WHERE, so joins are inner joins, raw SQL joins are not supported
+	//
+	//	UPDATE `users` SET ... FROM `companies` `Company` WHERE `users`.`company_id` = `Company`.`id` AND ...
+	//	DELETE FROM `users` USING `companies` `Company` WHERE `users`.`company_id` = `Company`.`id` AND ...
+	JoinStyleFrom JoinStyle = "FROM"
+	// JoinStyleJoin joined tables are joined to the updating or deleting table
+	//
+	//	UPDATE `users` LEFT JOIN `companies` `Company` ON ... SET `users`.`name`=? WHERE ...
+	//	DELETE `users` FROM `users` LEFT JOIN `companies` `Company` ON ... WHERE ...
+	JoinStyleJoin JoinStyle = "JOIN"
+	// JoinStyleSubQuery rows are filtered by primary keys queried with the joins and conditions, joined tables can't be
+	// referenced by updating values
+	//
+	//	DELETE FROM `users` WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON ... WHERE ...)
+	JoinStyleSubQuery JoinStyle = "SUBQUERY"
+)
+
+// updateJoins writes joins of the statement into the update clauses with style
+func updateJoins(db *gorm.DB, style JoinStyle) {
+	joins := buildJoins(db, nil)
+
+	switch style {
+	case JoinStyleJoin:
+		db.Statement.AddClause(clause.Update{Joins: joins})
+
+		// joined tables may have columns with the same name
+		if c, ok := db.Statement.Clauses["SET"]; ok {
+			if set, ok := c.Expression.(clause.Set); ok {
+				qualifiedSet := make(clause.Set, len(set))
+				for idx, assignment := range set {
+					if assignment.Column.Table == "" {
+						assignment.Column.Table = clause.CurrentTable
+					}
+					qualifiedSet[idx] = assignment
+				}
+				c.Expression = qualifiedSet
+				db.Statement.Clauses["SET"] = c
+			}
+		}
+	case JoinStyleFrom:
+		if tables, conds, ok := joinedTables(db, joins); ok {
+			fromClause := clause.From{}
+			if v, ok := db.Statement.Clauses["FROM"].Expression.(clause.From); ok {
+				fromClause = v
+			}
+			fromClause.Tables = append(fromClause.Tables[:len(fromClause.Tables):len(fromClause.Tables)], tables...)
+
+			db.Statement.AddClause(fromClause)
+			if len(conds) > 0 {
+				db.Statement.AddClause(clause.Where{Exprs: conds})
+			}
+		}
+	default:
+		filterByJoinedQuery(db, joins)
+	}
+}
+
+// deleteJoins writes joins of the statement into the delete clauses with style
+func deleteJoins(db *gorm.DB, style JoinStyle) {
+	joins := buildJoins(db, nil)
+
+	// soft deletes are written as updates, whose FROM can't be taken by the USING tables
+	if style == JoinStyleFrom && db.Statement.Schema != nil && len(db.Statement.Schema.DeleteClauses) > 0 && !db.Statement.Unscoped {
+		style = JoinStyleSubQuery
+	}
+
+	switch style {
+	case JoinStyleJoin:
+		fromClause := clause.From{}
+		if v, ok := db.Statement.Clauses["FROM"].Expression.(clause.From); ok {
+			fromClause = v
+		}
+		fromClause.Joins = append(fromClause.Joins[:len(fromClause.Joins):len(fromClause.Joins)], joins...)
+
+		db.Statement.AddClause(clause.Delete{Tables: []clause.Table{{Name: clause.CurrentTable}}})
+		db.Statement.AddClause(fromClause)
+		// used if the delete is written as an update, e.g. soft delete
+		db.Statement.AddClause(clause.Update{Joins: joins})
+	case JoinStyleFrom:
+		if tables, conds, ok := joinedTables(db, joins); ok {
+			db.Statement.AddClause(clause.Using{Tables: tables})
+			if len(conds) > 0 {
+				db.Statement.AddClause(clause.Where{Exprs: conds})
+			}
+		}
+	default:
+		filterByJoinedQuery(db, joins)
+	}
+}
+
+// joinedTables returns tables and join conditions of joins
+func joinedTables(db *gorm.DB, joins []clause.Join) (tables []clause.Table, conds []clause.Expression, ok bool) {
+	for _, join := range joins {
+		if join.Expression != nil {
+			db.AddError(fmt.Errorf("%w: raw SQL joins can't be written as %s tables, join relations instead", gorm.ErrNotImplemented, JoinStyleFrom))
+			return nil, nil, false
+		}
+
+		tables = append(tables, join.Table)
+		conds = append(conds, join.ON.Exprs...)
+	}
+	return tables, conds, true
+}
+
+// filterByJoinedQuery replaces the conditions with a primary keys filter, the primary keys are queried with the joins
+// and the original conditions
+func filterByJoinedQuery(db *gorm.DB, joins []clause.Join) {
+	if db.Statement.Schema == nil || len(db.Statement.Schema.PrimaryFields) == 0 {
+		db.AddError(fmt.Errorf("%w when using joins with %s", gorm.ErrPrimaryKeyRequired, JoinStyleSubQuery))
+		return
+	}
+
+	query := joinedQuery{Joins: joins}
+	for _, field := range db.Statement.Schema.PrimaryFields {
+		query.Columns = append(query.Columns, clause.Column{Table: clause.CurrentTable, Name: field.DBName})
+	}
+
+	c := db.Statement.Clauses["WHERE"]
+	if where, ok := c.Expression.(clause.Where); ok {
+		query.Where = where
+	}
+	c.Name = "WHERE"
+	c.Expression = clause.Where{Exprs: []clause.Expression{query}}
+	db.Statement.Clauses["WHERE"] = c
+
+	// soft delete conditions are moved into the query too
+	delete(db.Statement.Clauses, "soft_delete_enabled")
+}
+
+// joinedQuery primary keys of current table are in the query with joins
+//
+//	`users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON ... WHERE ...)
+type joinedQuery struct {
+	Columns []clause.Column
+	Joins   []clause.Join
+	Where   clause.Where
+}
+
+func (query joinedQuery) Build(builder clause.Builder) {
+	if len(query.Columns) > 1 {
+		builder.WriteByte('(')
+		writeColumns(builder, query.Columns)
+		builder.WriteByte(')')
+	} else {
+		writeColumns(builder, query.Columns)
+	}
+
+	builder.WriteString(" IN (SELECT ")
+	writeColumns(builder, query.Columns)
+	builder.WriteString(" FROM ")
+	clause.From{Joins: query.Joins}.Build(builder)
+
+	if len(query.Where.Exprs) > 0 {
+		builder.WriteString(" WHERE ")
+		query.Where.Build(builder)
+	}
+	builder.WriteByte(')')
+}
+
+func writeColumns(builder clause.Builder, columns []clause.Column) {
+	for idx, column := range columns {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(column)
+	}
+}
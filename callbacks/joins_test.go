@@ -0,0 +1,87 @@
+package callbacks_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+)
+
+type joinStyleDialector struct {
+	tests.DummyDialector
+	config callbacks.Config
+}
+
+func (d joinStyleDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &d.config)
+	return nil
+}
+
+func TestJoinStyles(t *testing.T) {
+	results := []struct {
+		Name   string
+		Config callbacks.Config
+		Update string
+		Delete string
+		Soft   string
+	}{
+		{
+			Name:   "join",
+			Config: callbacks.Config{UpdateClauses: []string{"UPDATE", "SET", "WHERE"}},
+			Update: "UPDATE `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` SET `users`.`name`=?,`users`.`updated_at`=? WHERE Company.name = ? AND `users`.`deleted_at` IS NULL",
+			Delete: "DELETE `users` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?",
+			Soft:   "UPDATE `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` SET `users`.`deleted_at`=? WHERE Company.name = ? AND `users`.`deleted_at` IS NULL",
+		},
+		{
+			Name:   "from",
+			Config: callbacks.Config{UpdateClauses: []string{"UPDATE", "SET", "FROM", "WHERE"}},
+			Update: "UPDATE `users` SET `name`=?,`updated_at`=? FROM `companies` `Company` WHERE Company.name = ? AND `users`.`deleted_at` IS NULL AND `users`.`company_id` = `Company`.`id`",
+			Delete: "DELETE FROM `users` WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?)",
+			Soft:   "UPDATE `users` SET `deleted_at`=? WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?) AND `users`.`deleted_at` IS NULL",
+		},
+		{
+			Name:   "using",
+			Config: callbacks.Config{UpdateJoinStyle: callbacks.JoinStyleFrom, DeleteJoinStyle: callbacks.JoinStyleFrom},
+			Update: "UPDATE `users` SET `name`=?,`updated_at`=? FROM `companies` `Company` WHERE Company.name = ? AND `users`.`deleted_at` IS NULL AND `users`.`company_id` = `Company`.`id`",
+			Delete: "DELETE FROM `users` USING `companies` `Company` WHERE Company.name = ? AND `users`.`company_id` = `Company`.`id`",
+			Soft:   "UPDATE `users` SET `deleted_at`=? WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?) AND `users`.`deleted_at` IS NULL",
+		},
+		{
+			Name:   "subquery",
+			Config: callbacks.Config{UpdateJoinStyle: callbacks.JoinStyleSubQuery},
+			Update: "UPDATE `users` SET `name`=?,`updated_at`=? WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ? AND `users`.`deleted_at` IS NULL)",
+			Delete: "DELETE FROM `users` WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?)",
+			Soft:   "UPDATE `users` SET `deleted_at`=? WHERE `users`.`id` IN (SELECT `users`.`id` FROM `users` LEFT JOIN `companies` `Company` ON `users`.`company_id` = `Company`.`id` WHERE Company.name = ?) AND `users`.`deleted_at` IS NULL",
+		},
+	}
+
+	for _, result := range results {
+		t.Run(result.Name, func(t *testing.T) {
+			db, err := gorm.Open(joinStyleDialector{config: result.Config}, &gorm.Config{DryRun: true, Logger: logger.Discard})
+			if err != nil {
+				t.Fatalf("failed to open db, got error %v", err)
+			}
+
+			tx := db.Model(&tests.User{}).Joins("Company").Where("Company.name = ?", "jinzhu").Update("name", "jinzhu2")
+			if sql := tx.Statement.SQL.String(); tx.Error != nil || sql != result.Update {
+				t.Errorf("joined update SQL is not equal, expects %v, but got %v, error %v", result.Update, sql, tx.Error)
+			}
+
+			tx = db.Unscoped().Joins("Company").Where("Company.name = ?", "jinzhu").Delete(&tests.User{})
+			if sql := tx.Statement.SQL.String(); tx.Error != nil || sql != result.Delete {
+				t.Errorf("joined delete SQL is not equal, expects %v, but got %v, error %v", result.Delete, sql, tx.Error)
+			}
+
+			tx = db.Joins("Company").Where("Company.name = ?", "jinzhu").Delete(&tests.User{})
+			if sql := tx.Statement.SQL.String(); tx.Error != nil || sql != result.Soft {
+				t.Errorf("joined soft delete SQL is not equal, expects %v, but got %v, error %v", result.Soft, sql, tx.Error)
+			}
+
+			if err := db.Unscoped().Joins("Company").Delete(&tests.User{}).Error; err != gorm.ErrMissingWhereClause {
+				t.Errorf("joined delete without conditions should returns ErrMissingWhereClause, got %v", err)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package callbacks
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -114,56 +117,155 @@ func preloadEntryPoint(db *gorm.DB, joins []string, relationships *schema.Relati
 		return joined, nestedJoins
 	}
 
+	preloadFuncs := make([]func() error, 0, len(preloadNames))
 	for _, name := range preloadNames {
+		name := name
 		if relations := relationships.EmbeddedRelations[name]; relations != nil {
-			if err := preloadEntryPoint(db, joins, relations, preloadMap[name], associationsConds); err != nil {
-				return err
-			}
+			// preloads of embedded relations run on a copy as they reset its table
+			tx := db.Session(&gorm.Session{Context: db.Statement.Context, SkipHooks: db.Statement.SkipHooks})
+			preloadFuncs = append(preloadFuncs, func() error {
+				return preloadEntryPoint(tx, joins, relations, preloadMap[name], associationsConds)
+			})
 		} else if rel := relationships.Relations[name]; rel != nil {
 			if joined, nestedJoins := isJoined(name); joined {
-				switch rv := db.Statement.ReflectValue; rv.Kind() {
-				case reflect.Slice, reflect.Array:
-					if rv.Len() > 0 {
-						reflectValue := rel.FieldSchema.MakeSlice().Elem()
-						for i := 0; i < rv.Len(); i++ {
-							frv := rel.Field.ReflectValueOf(db.Statement.Context, rv.Index(i))
-							if frv.Kind() != reflect.Ptr {
-								reflectValue = reflect.Append(reflectValue, frv.Addr())
-							} else {
-								if frv.IsNil() {
-									continue
+				preloadFuncs = append(preloadFuncs, func() error {
+					switch rv := db.Statement.ReflectValue; rv.Kind() {
+					case reflect.Slice, reflect.Array:
+						if rv.Len() > 0 {
+							reflectValue := rel.FieldSchema.MakeSlice().Elem()
+							for i := 0; i < rv.Len(); i++ {
+								frv := rel.Field.ReflectValueOf(db.Statement.Context, rv.Index(i))
+								if frv.Kind() != reflect.Ptr {
+									reflectValue = reflect.Append(reflectValue, frv.Addr())
+								} else {
+									if frv.IsNil() {
+										continue
+									}
+									reflectValue = reflect.Append(reflectValue, frv)
 								}
-								reflectValue = reflect.Append(reflectValue, frv)
 							}
-						}
 
+							tx := preloadDB(db, reflectValue, reflectValue.Interface())
+							if err := preloadEntryPoint(tx, nestedJoins, &tx.Statement.Schema.Relationships, preloadMap[name], associationsConds); err != nil {
+								return err
+							}
+						}
+					case reflect.Struct, reflect.Pointer:
+						reflectValue := rel.Field.ReflectValueOf(db.Statement.Context, rv)
 						tx := preloadDB(db, reflectValue, reflectValue.Interface())
 						if err := preloadEntryPoint(tx, nestedJoins, &tx.Statement.Schema.Relationships, preloadMap[name], associationsConds); err != nil {
 							return err
 						}
+					default:
+						return gorm.ErrInvalidData
 					}
-				case reflect.Struct, reflect.Pointer:
-					reflectValue := rel.Field.ReflectValueOf(db.Statement.Context, rv)
-					tx := preloadDB(db, reflectValue, reflectValue.Interface())
-					if err := preloadEntryPoint(tx, nestedJoins, &tx.Statement.Schema.Relationships, preloadMap[name], associationsConds); err != nil {
-						return err
-					}
-				default:
-					return gorm.ErrInvalidData
-				}
+					return nil
+				})
 			} else {
 				tx := db.Table("").Session(&gorm.Session{Context: db.Statement.Context, SkipHooks: db.Statement.SkipHooks})
 				tx.Statement.ReflectValue = db.Statement.ReflectValue
 				tx.Statement.Unscoped = db.Statement.Unscoped
-				if err := preload(tx, rel, append(preloads[name], associationsConds...), preloadMap[name]); err != nil {
-					return err
-				}
+				preloadFuncs = append(preloadFuncs, func() error {
+					return preload(tx, rel, append(preloads[name], associationsConds...), preloadMap[name])
+				})
 			}
 		} else {
 			return fmt.Errorf("%s: %w for schema %s", name, gorm.ErrUnsupportedRelation, db.Statement.Schema.Name)
 		}
 	}
-	return nil
+	return runPreloads(db, preloadFuncs)
+}
+
+// runPreloads runs sibling preloads, they run concurrently on separate connections with ConcurrentPreload out of
+// transactions, errors of them are joined in order
+func runPreloads(db *gorm.DB, preloadFuncs []func() error) error {
+	concurrent := db.ConcurrentPreload > 1 && len(preloadFuncs) > 1
+	switch db.Statement.ConnPool.(type) {
+	case gorm.TxCommitter, *sql.Conn:
+		concurrent = false
+	}
+
+	if !concurrent {
+		for _, fc := range preloadFuncs {
+			if err := fc(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		wg        sync.WaitGroup
+		errs      = make([]error, len(preloadFuncs))
+		panics    = make([]interface{}, len(preloadFuncs))
+		semaphore = make(chan struct{}, db.ConcurrentPreload)
+	)
+
+	for idx, fc := range preloadFuncs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(idx int, fc func() error) {
+			defer func() {
+				panics[idx] = recover()
+				<-semaphore
+				wg.Done()
+			}()
+			errs[idx] = fc()
+		}(idx, fc)
+	}
+	wg.Wait()
+
+	var joined preloadErrors
+	for idx := range preloadFuncs {
+		if panics[idx] != nil {
+			panic(panics[idx])
+		}
+
+		if errs[idx] != nil {
+			joined = append(joined, errs[idx])
+		}
+	}
+
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	}
+	return joined
+}
+
+// preloadErrors errors of concurrent preloads, all of them are matched by errors.Is and errors.As
+type preloadErrors []error
+
+func (errs preloadErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (errs preloadErrors) Unwrap() []error {
+	return errs
+}
+
+func (errs preloadErrors) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (errs preloadErrors) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
 }
 
 func preloadDB(db *gorm.DB, reflectValue reflect.Value, dest interface{}) *gorm.DB {
@@ -349,3 +451,152 @@ func preload(tx *gorm.DB, rel *schema.Relationship, conds []interface{}, preload
 
 	return tx.Error
 }
+
+// preloadCounts preloads counts of associations to their count fields
+func preloadCounts(db *gorm.DB, counts map[string][]interface{}) error {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rel := db.Statement.Schema.Relationships.Relations[name]
+		if rel == nil {
+			return fmt.Errorf("%s: %w for schema %s", name, gorm.ErrUnsupportedRelation, db.Statement.Schema.Name)
+		}
+
+		var countField *schema.Field
+		for _, field := range db.Statement.Schema.Fields {
+			if field.TagSettings["COUNT"] == name {
+				countField = field
+				break
+			}
+		}
+
+		if countField == nil {
+			return fmt.Errorf("%s: %w, no count field of it for schema %s", name, gorm.ErrInvalidField, db.Statement.Schema.Name)
+		}
+
+		tx := db.Table("").Session(&gorm.Session{Context: db.Statement.Context, SkipHooks: db.Statement.SkipHooks})
+		tx.Statement.ReflectValue = db.Statement.ReflectValue
+		tx.Statement.Unscoped = db.Statement.Unscoped
+		if err := preloadCount(tx, rel, countField, counts[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preloadCount preloads counts of the has many or many2many relationship rel with a query grouped by foreign keys
+func preloadCount(tx *gorm.DB, rel *schema.Relationship, countField *schema.Field, conds []interface{}) error {
+	if rel.Type != schema.HasMany && rel.Type != schema.Many2Many {
+		return fmt.Errorf("%s: %w, counts of %s relations", rel.Name, gorm.ErrUnsupportedRelation, rel.Type)
+	}
+
+	var (
+		reflectValue = tx.Statement.ReflectValue
+		foreignTable = clause.CurrentTable
+		foreignKeys  []string
+		ownerFields  []*schema.Field
+		joinConds    []clause.Expression
+		inlineConds  []interface{}
+	)
+
+	tx = tx.Model(reflect.New(rel.FieldSchema.ModelType).Interface())
+	if rel.JoinTable != nil {
+		foreignTable = rel.JoinTable.Table
+	}
+
+	for _, ref := range rel.References {
+		if ref.OwnPrimaryKey {
+			foreignKeys = append(foreignKeys, ref.ForeignKey.DBName)
+			ownerFields = append(ownerFields, ref.PrimaryKey)
+		} else if ref.PrimaryValue != "" {
+			tx = tx.Where(clause.Eq{Column: clause.Column{Table: foreignTable, Name: ref.ForeignKey.DBName}, Value: ref.PrimaryValue})
+		} else {
+			joinConds = append(joinConds, clause.Eq{
+				Column: clause.Column{Table: foreignTable, Name: ref.ForeignKey.DBName},
+				Value:  clause.Column{Table: clause.CurrentTable, Name: ref.PrimaryKey.DBName},
+			})
+		}
+	}
+
+	// reset counts of records without associations
+	switch reflectValue.Kind() {
+	case reflect.Struct:
+		tx.AddError(countField.Set(tx.Statement.Context, reflectValue, 0))
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < reflectValue.Len(); i++ {
+			tx.AddError(countField.Set(tx.Statement.Context, reflectValue.Index(i), 0))
+		}
+	}
+
+	identityMap, ownerValues := schema.GetIdentityFieldValuesMap(tx.Statement.Context, reflectValue, ownerFields)
+	if tx.Error != nil || len(ownerValues) == 0 {
+		return tx.Error
+	}
+
+	if len(joinConds) > 0 {
+		tx = tx.Joins("INNER JOIN ? ON ?", clause.Table{Name: rel.JoinTable.Table}, clause.And(joinConds...))
+	}
+
+	column, values := schema.ToQueryValues(foreignTable, foreignKeys, ownerValues)
+	tx = tx.Where(clause.IN{Column: column, Values: values})
+
+	for _, cond := range conds {
+		if fc, ok := cond.(func(*gorm.DB) *gorm.DB); ok {
+			tx = fc(tx)
+		} else {
+			inlineConds = append(inlineConds, cond)
+		}
+	}
+
+	if len(inlineConds) > 0 {
+		tx = tx.Where(inlineConds[0], inlineConds[1:]...)
+	}
+
+	groupColumns := make([]clause.Column, len(foreignKeys))
+	selectVars := make([]interface{}, len(foreignKeys))
+	for idx, foreignKey := range foreignKeys {
+		groupColumns[idx] = clause.Column{Table: foreignTable, Name: foreignKey}
+		selectVars[idx] = groupColumns[idx]
+	}
+
+	rows, err := tx.Select(strings.Repeat("?,", len(foreignKeys))+"COUNT(*)", selectVars...).
+		Clauses(clause.GroupBy{Columns: groupColumns}).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		count       int64
+		dests       = make([]interface{}, len(ownerFields)+1)
+		fieldValues = make([]interface{}, len(ownerFields))
+	)
+	for rows.Next() {
+		// foreign keys are scanned as the owner's primary keys to match identities
+		for idx, field := range ownerFields {
+			dests[idx] = reflect.New(field.FieldType).Interface()
+		}
+		dests[len(ownerFields)] = &count
+
+		if err := rows.Scan(dests...); err != nil {
+			return err
+		}
+
+		for idx := range ownerFields {
+			fieldValues[idx] = reflect.ValueOf(dests[idx]).Elem().Interface()
+		}
+
+		for _, data := range identityMap[utils.ToStringKey(fieldValues...)] {
+			tx.AddError(countField.Set(tx.Statement.Context, data, count))
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return tx.Error
+}
@@ -101,6 +101,7 @@ func BuildQuerySQL(db *gorm.DB) {
 		}
 
 		// inline joins
+		var buildClauses []string
 		fromClause := clause.From{}
 		if v, ok := db.Statement.Clauses["FROM"].Expression.(clause.From); ok {
 			fromClause = v
@@ -114,168 +115,343 @@ func BuildQuerySQL(db *gorm.DB) {
 				}
 			}
 
-			specifiedRelationsName := map[string]string{clause.CurrentTable: clause.CurrentTable}
+			var (
+				aggregator, _ = db.Dialector.(gorm.JSONAggregator)
+				nestedJoins   = map[string]bool{}
+				aggregated    []clause.Expression
+				hasManyJoined bool
+			)
+
 			for _, join := range db.Statement.Joins {
-				if db.Statement.Schema != nil {
-					var isRelations bool // is relations or raw sql
-					var relations []*schema.Relationship
-					relation, ok := db.Statement.Schema.Relationships.Relations[join.Name]
-					if ok {
-						isRelations = true
-						relations = append(relations, relation)
-					} else {
-						// handle nested join like "Manager.Company"
-						nestedJoinNames := strings.Split(join.Name, ".")
-						if len(nestedJoinNames) > 1 {
-							isNestedJoin := true
-							guessNestedRelations := make([]*schema.Relationship, 0, len(nestedJoinNames))
-							currentRelations := db.Statement.Schema.Relationships.Relations
-							for _, relname := range nestedJoinNames {
-								// incomplete match, only treated as raw sql
-								if relation, ok = currentRelations[relname]; ok {
-									guessNestedRelations = append(guessNestedRelations, relation)
-									currentRelations = relation.FieldSchema.Relationships.Relations
-								} else {
-									isNestedJoin = false
-									break
-								}
-							}
+				if idx := strings.LastIndex(join.Name, "."); idx > 0 {
+					nestedJoins[join.Name[:idx]] = true
+				}
+			}
 
-							if isNestedJoin {
-								isRelations = true
-								relations = guessNestedRelations
-							}
+			fromClause.Joins = append(fromClause.Joins, buildJoins(db, func(tableAliasName string, relation *schema.Relationship, selects, omits []string, join clause.Join) bool {
+				columnStmt := gorm.Statement{
+					Table: tableAliasName, DB: db, Schema: relation.FieldSchema,
+					Selects: selects, Omits: omits,
+				}
+
+				var columns []clause.Column
+				selectColumns, restricted := columnStmt.SelectAndOmitColumns(false, false)
+				for _, s := range relation.FieldSchema.DBNames {
+					if v, ok := selectColumns[s]; (ok && v) || (!ok && !restricted) {
+						columns = append(columns, clause.Column{Table: tableAliasName, Name: s})
+					}
+				}
+
+				if relation.Type == schema.HasMany {
+					// has many relations are aggregated to JSON arrays with subqueries if supported, e.g.
+					// (SELECT JSON_AGG(...) FROM pets Pets WHERE users.id = Pets.user_id) AS Pets
+					if aggregator != nil && tableAliasName == relation.Name && join.Type == clause.LeftJoin &&
+						join.Expression == nil && !nestedJoins[relation.Name] {
+						aggregated = append(aggregated, clause.Expr{
+							SQL:  "(SELECT ? FROM ? ?) AS ?",
+							Vars: []interface{}{aggregator.JSONArrayAgg(columns), join.Table, join.ON, clause.Column{Name: relation.Name}},
+						})
+						return false
+					}
+					hasManyJoined = true
+				}
+
+				for _, column := range columns {
+					column.Alias = utils.NestedRelationName(tableAliasName, column.Name)
+					clauseSelect.Columns = append(clauseSelect.Columns, column)
+				}
+				return true
+			})...)
+
+			db.Statement.AddClause(fromClause)
+
+			if len(aggregated) > 0 {
+				exprs := make([]clause.Expression, 0, len(clauseSelect.Columns)+len(aggregated))
+				for _, column := range clauseSelect.Columns {
+					exprs = append(exprs, clause.Expr{SQL: "?", Vars: []interface{}{column}})
+				}
+				clauseSelect.Expression = clause.CommaExpression{Exprs: append(exprs, aggregated...)}
+			}
+
+			// limits of statements joining has many relations are applied to parents by joining to a derived
+			// table, e.g. FROM (SELECT * FROM users WHERE ... LIMIT 10) users LEFT JOIN pets Pets ON ... WHERE ...
+			if limit, ok := db.Statement.Clauses["LIMIT"].Expression.(clause.Limit); ok && hasManyJoined && (limit.Limit != nil || limit.Offset > 0) {
+				for _, name := range db.Statement.BuildClauses {
+					if name != "LIMIT" {
+						buildClauses = append(buildClauses, name)
+					}
+				}
+
+				// the from clause is restored after building as joins of it are cleared after querying
+				fromClause := db.Statement.Clauses["FROM"]
+				db.Statement.Clauses["FROM"] = clause.Clause{Name: "FROM", Expression: limitedFrom{From: fromClause.Expression.(clause.From)}}
+				defer func() {
+					db.Statement.Clauses["FROM"] = fromClause
+				}()
+			}
+		} else {
+			db.Statement.AddClauseIfNotExists(clause.From{})
+		}
+
+		if buildClauses == nil {
+			buildClauses = db.Statement.BuildClauses
+		}
+
+		db.Statement.AddClauseIfNotExists(clauseSelect)
+
+		db.Statement.Build(buildClauses...)
+	}
+}
+
+// limitedFrom from clause joining to a derived table of the current table with conditions and limits of the statement,
+// conditions may reference joined tables, so parents are filtered with a subquery joining them, e.g.
+// (SELECT * FROM users WHERE users.id IN (SELECT users.id FROM users LEFT JOIN pets Pets ON ... WHERE ...) LIMIT 10)
+type limitedFrom struct {
+	clause.From
+}
+
+func (from limitedFrom) Build(builder clause.Builder) {
+	stmt := builder.(*gorm.Statement)
+
+	builder.WriteString("(SELECT * FROM ")
+	builder.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+	if _, ok := stmt.Clauses["WHERE"]; ok {
+		if len(stmt.Schema.PrimaryFields) == 0 {
+			builder.WriteByte(' ')
+			stmt.Build("WHERE")
+		} else {
+			builder.WriteString(" WHERE ")
+			from.writePrimaryKeys(stmt)
+			builder.WriteString(" IN (SELECT ")
+			from.writePrimaryKeys(stmt)
+			builder.WriteString(" FROM ")
+			builder.WriteQuoted(clause.Table{Name: clause.CurrentTable})
+			for _, join := range from.Joins {
+				builder.WriteByte(' ')
+				join.Build(builder)
+			}
+			builder.WriteByte(' ')
+			stmt.Build("WHERE")
+			builder.WriteByte(')')
+		}
+	}
+
+	// orders of joined tables are not applied to parents as they are not joined in the derived table
+	if orderBy, ok := stmt.Clauses["ORDER BY"].Expression.(clause.OrderBy); ok {
+		columns := make([]clause.OrderByColumn, 0, len(orderBy.Columns))
+		for _, column := range orderBy.Columns {
+			if !from.referencesJoins(column.Column) {
+				columns = append(columns, column)
+			}
+		}
+
+		if orderBy.Columns = columns; orderBy.Expression != nil || len(orderBy.Columns) > 0 {
+			builder.WriteString(" ORDER BY ")
+			orderBy.Build(builder)
+		}
+	}
+
+	if _, ok := stmt.Clauses["LIMIT"]; ok {
+		builder.WriteByte(' ')
+		stmt.Build("LIMIT")
+	}
+	builder.WriteString(") ")
+	builder.WriteQuoted(stmt.Table)
+
+	for _, join := range from.Joins {
+		builder.WriteByte(' ')
+		join.Build(builder)
+	}
+}
+
+// referencesJoins returns true if column is a column of joined tables or raw SQL referencing them
+func (from limitedFrom) referencesJoins(column clause.Column) bool {
+	for _, join := range from.Joins {
+		alias := join.Table.Alias
+		if alias == "" {
+			alias = join.Table.Name
+		}
+
+		if alias == "" {
+			continue
+		}
+
+		if column.Table == alias {
+			return true
+		}
+
+		if column.Table == "" && column.Raw {
+			for _, quoted := range []string{alias, "`" + alias + "`", `"` + alias + `"`, "[" + alias + "]"} {
+				if strings.Contains(column.Name, quoted+".") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// writePrimaryKeys writes primary keys of the current table, composite primary keys are written as row values
+func (from limitedFrom) writePrimaryKeys(stmt *gorm.Statement) {
+	primaryFields := stmt.Schema.PrimaryFields
+	if len(primaryFields) > 1 {
+		stmt.WriteByte('(')
+	}
+	for idx, field := range primaryFields {
+		if idx > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteQuoted(clause.Column{Table: clause.CurrentTable, Name: field.DBName})
+	}
+	if len(primaryFields) > 1 {
+		stmt.WriteByte(')')
+	}
+}
+
+// buildJoins converts the joins of the statement to join clauses, onRelation is called with the table alias and the
+// join clause of every joined relation, the join clause is skipped if it returns false
+func buildJoins(db *gorm.DB, onRelation func(tableAliasName string, relation *schema.Relationship, selects, omits []string, join clause.Join) bool) (joins []clause.Join) {
+	specifiedRelationsName := map[string]string{clause.CurrentTable: clause.CurrentTable}
+	for _, join := range db.Statement.Joins {
+		if db.Statement.Schema != nil {
+			var isRelations bool // is relations or raw sql
+			var relations []*schema.Relationship
+			relation, ok := db.Statement.Schema.Relationships.Relations[join.Name]
+			if ok {
+				isRelations = true
+				relations = append(relations, relation)
+			} else {
+				// handle nested join like "Manager.Company"
+				nestedJoinNames := strings.Split(join.Name, ".")
+				if len(nestedJoinNames) > 1 {
+					isNestedJoin := true
+					guessNestedRelations := make([]*schema.Relationship, 0, len(nestedJoinNames))
+					currentRelations := db.Statement.Schema.Relationships.Relations
+					for _, relname := range nestedJoinNames {
+						// incomplete match, only treated as raw sql
+						if relation, ok = currentRelations[relname]; ok {
+							guessNestedRelations = append(guessNestedRelations, relation)
+							currentRelations = relation.FieldSchema.Relationships.Relations
+						} else {
+							isNestedJoin = false
+							break
 						}
 					}
 
-					if isRelations {
-						genJoinClause := func(joinType clause.JoinType, tableAliasName string, parentTableName string, relation *schema.Relationship) clause.Join {
-							columnStmt := gorm.Statement{
-								Table: tableAliasName, DB: db, Schema: relation.FieldSchema,
-								Selects: join.Selects, Omits: join.Omits,
-							}
+					if isNestedJoin {
+						isRelations = true
+						relations = guessNestedRelations
+					}
+				}
+			}
 
-							selectColumns, restricted := columnStmt.SelectAndOmitColumns(false, false)
-							for _, s := range relation.FieldSchema.DBNames {
-								if v, ok := selectColumns[s]; (ok && v) || (!ok && !restricted) {
-									clauseSelect.Columns = append(clauseSelect.Columns, clause.Column{
-										Table: tableAliasName,
-										Name:  s,
-										Alias: utils.NestedRelationName(tableAliasName, s),
-									})
-								}
-							}
+			if isRelations {
+				genJoinClause := func(joinType clause.JoinType, tableAliasName string, parentTableName string, relation *schema.Relationship) clause.Join {
+					if join.Expression != nil {
+						return clause.Join{
+							Type:       join.JoinType,
+							Expression: join.Expression,
+						}
+					}
 
-							if join.Expression != nil {
-								return clause.Join{
-									Type:       join.JoinType,
-									Expression: join.Expression,
-								}
+					exprs := make([]clause.Expression, len(relation.References))
+					for idx, ref := range relation.References {
+						if ref.OwnPrimaryKey {
+							exprs[idx] = clause.Eq{
+								Column: clause.Column{Table: parentTableName, Name: ref.PrimaryKey.DBName},
+								Value:  clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
 							}
-
-							exprs := make([]clause.Expression, len(relation.References))
-							for idx, ref := range relation.References {
-								if ref.OwnPrimaryKey {
-									exprs[idx] = clause.Eq{
-										Column: clause.Column{Table: parentTableName, Name: ref.PrimaryKey.DBName},
-										Value:  clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
-									}
-								} else {
-									if ref.PrimaryValue == "" {
-										exprs[idx] = clause.Eq{
-											Column: clause.Column{Table: parentTableName, Name: ref.ForeignKey.DBName},
-											Value:  clause.Column{Table: tableAliasName, Name: ref.PrimaryKey.DBName},
-										}
-									} else {
-										exprs[idx] = clause.Eq{
-											Column: clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
-											Value:  ref.PrimaryValue,
-										}
-									}
+						} else {
+							if ref.PrimaryValue == "" {
+								exprs[idx] = clause.Eq{
+									Column: clause.Column{Table: parentTableName, Name: ref.ForeignKey.DBName},
+									Value:  clause.Column{Table: tableAliasName, Name: ref.PrimaryKey.DBName},
+								}
+							} else {
+								exprs[idx] = clause.Eq{
+									Column: clause.Column{Table: tableAliasName, Name: ref.ForeignKey.DBName},
+									Value:  ref.PrimaryValue,
 								}
 							}
+						}
+					}
 
-							{
-								onStmt := gorm.Statement{Table: tableAliasName, DB: db, Clauses: map[string]clause.Clause{}}
-								for _, c := range relation.FieldSchema.QueryClauses {
-									onStmt.AddClause(c)
-								}
+					{
+						onStmt := gorm.Statement{Table: tableAliasName, DB: db, Clauses: map[string]clause.Clause{}}
+						for _, c := range relation.FieldSchema.QueryClauses {
+							onStmt.AddClause(c)
+						}
 
-								if join.On != nil {
-									onStmt.AddClause(join.On)
-								}
+						if join.On != nil {
+							onStmt.AddClause(join.On)
+						}
 
-								if cs, ok := onStmt.Clauses["WHERE"]; ok {
-									if where, ok := cs.Expression.(clause.Where); ok {
-										where.Build(&onStmt)
-
-										if onSQL := onStmt.SQL.String(); onSQL != "" {
-											vars := onStmt.Vars
-											for idx, v := range vars {
-												bindvar := strings.Builder{}
-												onStmt.Vars = vars[0 : idx+1]
-												db.Dialector.BindVarTo(&bindvar, &onStmt, v)
-												onSQL = strings.Replace(onSQL, bindvar.String(), "?", 1)
-											}
-
-											exprs = append(exprs, clause.Expr{SQL: onSQL, Vars: vars})
-										}
+						if cs, ok := onStmt.Clauses["WHERE"]; ok {
+							if where, ok := cs.Expression.(clause.Where); ok {
+								where.Build(&onStmt)
+
+								if onSQL := onStmt.SQL.String(); onSQL != "" {
+									vars := onStmt.Vars
+									for idx, v := range vars {
+										bindvar := strings.Builder{}
+										onStmt.Vars = vars[0 : idx+1]
+										db.Dialector.BindVarTo(&bindvar, &onStmt, v)
+										onSQL = strings.Replace(onSQL, bindvar.String(), "?", 1)
 									}
-								}
-							}
 
-							return clause.Join{
-								Type:  joinType,
-								Table: clause.Table{Name: relation.FieldSchema.Table, Alias: tableAliasName},
-								ON:    clause.Where{Exprs: exprs},
+									exprs = append(exprs, clause.Expr{SQL: onSQL, Vars: vars})
+								}
 							}
 						}
+					}
 
-						parentTableName := clause.CurrentTable
-						for idx, rel := range relations {
-							// joins table alias like "Manager, Company, Manager__Company"
-							curAliasName := rel.Name
-							if parentTableName != clause.CurrentTable {
-								curAliasName = utils.NestedRelationName(parentTableName, curAliasName)
-							}
+					return clause.Join{
+						Type:  joinType,
+						Table: clause.Table{Name: relation.FieldSchema.Table, Alias: tableAliasName},
+						ON:    clause.Where{Exprs: exprs},
+					}
+				}
 
-							if _, ok := specifiedRelationsName[curAliasName]; !ok {
-								aliasName := curAliasName
-								if idx == len(relations)-1 && join.Alias != "" {
-									aliasName = join.Alias
-								}
+				parentTableName := clause.CurrentTable
+				for idx, rel := range relations {
+					// joins table alias like "Manager, Company, Manager__Company"
+					curAliasName := rel.Name
+					if parentTableName != clause.CurrentTable {
+						curAliasName = utils.NestedRelationName(parentTableName, curAliasName)
+					}
 
-								fromClause.Joins = append(fromClause.Joins, genJoinClause(join.JoinType, aliasName, specifiedRelationsName[parentTableName], rel))
-								specifiedRelationsName[curAliasName] = aliasName
-							}
+					if _, ok := specifiedRelationsName[curAliasName]; !ok {
+						aliasName := curAliasName
+						if idx == len(relations)-1 && join.Alias != "" {
+							aliasName = join.Alias
+						}
 
-							parentTableName = curAliasName
+						joinClause := genJoinClause(join.JoinType, aliasName, specifiedRelationsName[parentTableName], rel)
+						if onRelation == nil || onRelation(aliasName, rel, join.Selects, join.Omits, joinClause) {
+							joins = append(joins, joinClause)
 						}
-					} else {
-						fromClause.Joins = append(fromClause.Joins, clause.Join{
-							Expression: clause.NamedExpr{SQL: join.Name, Vars: join.Conds},
-						})
+						specifiedRelationsName[curAliasName] = aliasName
 					}
-				} else {
-					fromClause.Joins = append(fromClause.Joins, clause.Join{
-						Expression: clause.NamedExpr{SQL: join.Name, Vars: join.Conds},
-					})
+
+					parentTableName = curAliasName
 				}
+			} else {
+				joins = append(joins, clause.Join{
+					Expression: clause.NamedExpr{SQL: join.Name, Vars: join.Conds},
+				})
 			}
-
-			db.Statement.AddClause(fromClause)
 		} else {
-			db.Statement.AddClauseIfNotExists(clause.From{})
+			joins = append(joins, clause.Join{
+				Expression: clause.NamedExpr{SQL: join.Name, Vars: join.Conds},
+			})
 		}
-
-		db.Statement.AddClauseIfNotExists(clauseSelect)
-
-		db.Statement.Build(db.Statement.BuildClauses...)
 	}
+
+	return
 }
 
 func Preload(db *gorm.DB) {
-	if db.Error == nil && len(db.Statement.Preloads) > 0 {
+	if db.Error == nil && (len(db.Statement.Preloads) > 0 || len(db.Statement.PreloadCounts) > 0) {
 		if db.Statement.Schema == nil {
 			db.AddError(fmt.Errorf("%w when using preload", gorm.ErrModelValueRequired))
 			return
@@ -291,7 +467,9 @@ func Preload(db *gorm.DB) {
 			return
 		}
 
-		db.AddError(preloadEntryPoint(tx, joins, &tx.Statement.Schema.Relationships, db.Statement.Preloads, db.Statement.Preloads[clause.Associations]))
+		if db.AddError(preloadEntryPoint(tx, joins, &tx.Statement.Schema.Relationships, db.Statement.Preloads, db.Statement.Preloads[clause.Associations])) == nil {
+			db.AddError(preloadCounts(tx, db.Statement.PreloadCounts))
+		}
 	}
 }
 
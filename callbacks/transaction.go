@@ -1,6 +1,8 @@
 package callbacks
 
 import (
+	"context"
+
 	"gorm.io/gorm"
 )
 
@@ -30,3 +32,55 @@ func CommitOrRollbackTransaction(db *gorm.DB) {
 		}
 	}
 }
+
+// registerTransactionHooks registers AfterCommit, AfterRollback hooks of the saved or deleted values to the
+// transaction of db, errors of them are logged as the transaction is completed already
+func registerTransactionHooks(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.SkipHooks || !(db.Statement.Schema.AfterCommit || db.Statement.Schema.AfterRollback) {
+		return
+	}
+
+	var values []interface{}
+	callMethod(db, func(value interface{}, tx *gorm.DB) bool {
+		_, afterCommit := value.(AfterCommitInterface)
+		_, afterRollback := value.(AfterRollbackInterface)
+		if afterCommit || afterRollback {
+			values = append(values, value)
+			return true
+		}
+		return false
+	})
+
+	call := func(ctx context.Context, fc func(value interface{}, tx *gorm.DB) error) {
+		// hooks are called out of the completed transaction
+		tx := db.Session(&gorm.Session{NewDB: true, Context: ctx})
+		tx.Statement.ConnPool = tx.ConnPool
+		for _, value := range values {
+			if err := fc(value, tx); err != nil {
+				db.Logger.Error(ctx, "failed to call transaction hook of %v, got error: %v", db.Statement.Schema, err)
+			}
+		}
+	}
+
+	if db.Statement.Schema.AfterCommit {
+		db.AfterCommit(func(ctx context.Context) {
+			call(ctx, func(value interface{}, tx *gorm.DB) error {
+				if i, ok := value.(AfterCommitInterface); ok {
+					return i.AfterCommit(tx)
+				}
+				return nil
+			})
+		})
+	}
+
+	if db.Statement.Schema.AfterRollback {
+		db.AfterRollback(func(ctx context.Context) {
+			call(ctx, func(value interface{}, tx *gorm.DB) error {
+				if i, ok := value.(AfterRollbackInterface); ok {
+					return i.AfterRollback(tx)
+				}
+				return nil
+			})
+		})
+	}
+}
@@ -65,6 +65,7 @@ func Update(config *Config) func(db *gorm.DB) {
 			for _, c := range db.Statement.Schema.UpdateClauses {
 				db.Statement.AddClause(c)
 			}
+			defer checkVersionLock(db)
 		}
 
 		if db.Statement.SQL.Len() == 0 {
@@ -79,6 +80,12 @@ func Update(config *Config) func(db *gorm.DB) {
 				}
 			}
 
+			if len(db.Statement.Joins) != 0 {
+				// joins may become conditions, check the conditions given by users
+				checkMissingWhereConditions(db)
+				updateJoins(db, config.UpdateJoinStyle)
+			}
+
 			db.Statement.Build(db.Statement.BuildClauses...)
 		}
 
@@ -134,6 +141,8 @@ func AfterUpdate(db *gorm.DB) {
 			return called
 		})
 	}
+
+	registerTransactionHooks(db)
 }
 
 // ConvertToAssignments convert to update assignments
@@ -309,5 +318,10 @@ func ConvertToAssignments(stmt *gorm.Statement) (set clause.Set) {
 		}
 	}
 
+	if c, ok := stmt.Clauses["version_lock"]; ok && len(set) > 0 {
+		if lock, ok := c.Expression.(gorm.VersionLock); ok {
+			set = append(set, lock.Assignments...)
+		}
+	}
 	return
 }
@@ -85,6 +85,107 @@ func (db *DB) Table(name string, args ...interface{}) (tx *DB) {
 	return
 }
 
+// With specify a common table expression named name, subquery could be *gorm.DB or clause.Expression
+//
+//	// query the users of a company through a CTE
+//	db.With("company_users", db.Model(&User{}).Where("company_id = ?", 1)).Table("company_users").Find(&users)
+//	// specify the CTE's column names
+//	db.With("names", db.Model(&User{}).Select("id", "name"), "user_id", "user_name").Table("names").Find(&results)
+func (db *DB) With(name string, subquery interface{}, columns ...string) (tx *DB) {
+	return with(db, false, name, subquery, columns...)
+}
+
+// WithRecursive specify a recursive common table expression named name, subquery could be *gorm.DB or clause.Expression
+//
+//	// walk a category tree from its roots
+//	db.WithRecursive("tree", db.Raw("SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id")).
+//		Table("tree").Find(&categories)
+func (db *DB) WithRecursive(name string, subquery interface{}, columns ...string) (tx *DB) {
+	return with(db, true, name, subquery, columns...)
+}
+
+func with(db *DB, recursive bool, name string, subquery interface{}, columns ...string) (tx *DB) {
+	tx = db.getInstance()
+
+	cte := clause.CTE{Name: name, Columns: columns}
+	switch v := subquery.(type) {
+	case clause.Expression:
+		cte.Subquery = v
+	case nil:
+		tx.AddError(fmt.Errorf("%w for common table expression %s", ErrSubQueryRequired, name))
+		return
+	default:
+		cte.Subquery = clause.Expr{SQL: "?", Vars: []interface{}{subquery}}
+	}
+
+	tx.Statement.AddClause(clause.With{Recursive: recursive, CTEs: []clause.CTE{cte}})
+	return
+}
+
+// Union query the combined results of queries, duplicated rows are removed, queries could be *gorm.DB or clause.Expression
+//
+// The combined result is queried as a derived table named after the current table, so it could be ordered, limited and
+// scanned like a normal table. Soft delete conditions are not applied again, they belong to the combined queries.
+//
+//	db.Union(db.Model(&User{}).Where("age < ?", 18), db.Model(&User{}).Where("age > ?", 60)).Order("name").Limit(10).Find(&users)
+//	// SELECT * FROM (SELECT * FROM `users` WHERE age < 18 AND `users`.`deleted_at` IS NULL UNION
+//	//   SELECT * FROM `users` WHERE age > 60 AND `users`.`deleted_at` IS NULL) AS `users` ORDER BY name LIMIT 10
+func (db *DB) Union(queries ...interface{}) (tx *DB) {
+	return setOperation(db, clause.Union, queries...)
+}
+
+// UnionAll query the combined results of queries, duplicated rows are kept, see Union
+func (db *DB) UnionAll(queries ...interface{}) (tx *DB) {
+	return setOperation(db, clause.UnionAll, queries...)
+}
+
+// Intersect query the rows returned by all queries, see Union
+func (db *DB) Intersect(queries ...interface{}) (tx *DB) {
+	return setOperation(db, clause.Intersect, queries...)
+}
+
+// Except query the rows returned by the first query but not by the others, see Union
+func (db *DB) Except(queries ...interface{}) (tx *DB) {
+	return setOperation(db, clause.Except, queries...)
+}
+
+func setOperation(db *DB, operator clause.SetOperator, queries ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if len(queries) < 2 {
+		tx.AddError(fmt.Errorf("%w: %s requires at least two queries", ErrSubQueryRequired, operator))
+		return
+	}
+
+	operation := clause.SetOperation{Operator: operator, Queries: make([]clause.Expression, 0, len(queries))}
+	for _, query := range queries {
+		switch v := query.(type) {
+		case clause.Expression:
+			operation.Queries = append(operation.Queries, v)
+		case nil:
+			tx.AddError(fmt.Errorf("%w: %s got nil query", ErrSubQueryRequired, operator))
+			return
+		default:
+			operation.Queries = append(operation.Queries, clause.Expr{SQL: "?", Vars: []interface{}{query}})
+		}
+	}
+
+	tx.Statement.TableExpr = &clause.Expr{SQL: "(?) AS ?", Vars: []interface{}{operation, derivedTableAlias{}}}
+	tx.Statement.Unscoped = true
+	return
+}
+
+// derivedTableAlias writes the current table name as the alias of a derived table, it is resolved when building so
+// conditions referring to the current table keep working
+type derivedTableAlias struct{}
+
+func (derivedTableAlias) Build(builder clause.Builder) {
+	if stmt, ok := builder.(*Statement); ok && stmt.Table != "" {
+		stmt.WriteQuoted(stmt.Table)
+		return
+	}
+	builder.WriteQuoted("t")
+}
+
 // Distinct specify distinct fields that you want querying
 //
 //	// Select distinct names of users
@@ -245,6 +346,9 @@ func (db *DB) Or(query interface{}, args ...interface{}) (tx *DB) {
 //	db.Joins("Account").Find(&user)
 //	db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
 //	db.Joins("Account", DB.Select("id").Where("user_id = users.id AND name = ?", "someName").Model(&Account{}))
+//	// has many relations are loaded by joined rows merged to their parents, or JSON aggregation subqueries if the
+//	// dialector implements JSONAggregator
+//	db.Joins("Pets").Limit(10).Find(&users)
 func (db *DB) Joins(query string, args ...interface{}) (tx *DB) {
 	return joins(db, clause.LeftJoin, query, args...)
 }
@@ -310,6 +414,11 @@ func (db *DB) Having(query interface{}, args ...interface{}) (tx *DB) {
 //		{Column: clause.Column{Name: "name"}, Desc: true},
 //		{Column: clause.Column{Name: "age"}, Desc: true},
 //	}})
+//	// order by an expression, e.g. a window function
+//	db.Order(clause.RowNumber(clause.Window{
+//		PartitionBy: []clause.Column{{Name: "user_id"}},
+//		OrderBy:     clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "created_at"}, Desc: true}}},
+//	}))
 func (db *DB) Order(value interface{}) (tx *DB) {
 	tx = db.getInstance()
 
@@ -328,6 +437,8 @@ func (db *DB) Order(value interface{}) (tx *DB) {
 				}},
 			})
 		}
+	case clause.Expression:
+		tx.Statement.AddClause(clause.OrderBy{Expression: v})
 	}
 	return
 }
@@ -401,6 +512,26 @@ func (db *DB) Preload(query string, args ...interface{}) (tx *DB) {
 	return
 }
 
+// PreloadCount preload counts of has many or many2many associations with given conditions to fields tagged with
+// `count`, counts of all records are queried with one grouped query
+//
+//	type Post struct {
+//	  ID           uint
+//	  Comments     []Comment
+//	  CommentCount int64 `gorm:"count:Comments"`
+//	}
+//
+//	// get all posts, and counts of their approved comments
+//	db.PreloadCount("Comments", "approved = ?", true).Find(&posts)
+func (db *DB) PreloadCount(name string, args ...interface{}) (tx *DB) {
+	tx = db.getInstance()
+	if tx.Statement.PreloadCounts == nil {
+		tx.Statement.PreloadCounts = map[string][]interface{}{}
+	}
+	tx.Statement.PreloadCounts[name] = args
+	return
+}
+
 // Attrs provide attributes used in [FirstOrCreate] or [FirstOrInit]
 //
 // Attrs only adds attributes if the record is not found.
@@ -0,0 +1,46 @@
+package clause
+
+// Case case expression, it is a searched case if Value is nil, Cond of Whens should be conditions,
+// otherwise it is a simple case compares Value with Cond of Whens, Else is omitted if nil, only Else is
+// built if there are no Whens
+//
+//	CASE WHEN `age` < ? THEN ? ELSE ? END
+//	CASE `id` WHEN ? THEN ? WHEN ? THEN ? END
+type Case struct {
+	Value interface{}
+	Whens []When
+	Else  interface{}
+}
+
+// When when branch of case expression, Cond and Then could be expressions, columns or values
+type When struct {
+	Cond interface{}
+	Then interface{}
+}
+
+// Build build case expression
+func (c Case) Build(builder Builder) {
+	if len(c.Whens) == 0 {
+		builder.AddVar(builder, c.Else)
+		return
+	}
+
+	builder.WriteString("CASE")
+	if c.Value != nil {
+		builder.WriteByte(' ')
+		builder.AddVar(builder, c.Value)
+	}
+
+	for _, when := range c.Whens {
+		builder.WriteString(" WHEN ")
+		builder.AddVar(builder, when.Cond)
+		builder.WriteString(" THEN ")
+		builder.AddVar(builder, when.Then)
+	}
+
+	if c.Else != nil {
+		builder.WriteString(" ELSE ")
+		builder.AddVar(builder, c.Else)
+	}
+	builder.WriteString(" END")
+}
@@ -0,0 +1,78 @@
+package clause_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestCase(t *testing.T) {
+	results := []struct {
+		Case   clause.Case
+		Result string
+		Vars   []interface{}
+	}{
+		{
+			Case: clause.Case{
+				Whens: []clause.When{
+					{Cond: clause.Lt{Column: "age", Value: 18}, Then: "minor"},
+					{Cond: clause.Expr{SQL: "age >= ? AND age < ?", Vars: []interface{}{18, 60}}, Then: "adult"},
+				},
+				Else: "senior",
+			},
+			Result: "CASE WHEN `age` < ? THEN ? WHEN age >= ? AND age < ? THEN ? ELSE ? END",
+			Vars:   []interface{}{18, "minor", 18, 60, "adult", "senior"},
+		},
+		{
+			Case: clause.Case{
+				Value: clause.Column{Name: "id"},
+				Whens: []clause.When{{Cond: 1, Then: "active"}, {Cond: 2, Then: "inactive"}},
+			},
+			Result: "CASE `id` WHEN ? THEN ? WHEN ? THEN ? END",
+			Vars:   []interface{}{1, "active", 2, "inactive"},
+		},
+		{
+			Case: clause.Case{
+				Value: clause.PrimaryColumn,
+				Whens: []clause.When{{Cond: 1, Then: clause.Expr{SQL: "age + ?", Vars: []interface{}{1}}}},
+				Else:  clause.Column{Table: clause.CurrentTable, Name: "age"},
+			},
+			Result: "CASE `users`.`id` WHEN ? THEN age + ? ELSE `users`.`age` END",
+			Vars:   []interface{}{1, 1},
+		},
+		{
+			Case:   clause.Case{Else: clause.Column{Name: "age"}},
+			Result: "`age`",
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			user, _ := schema.Parse(&tests.User{}, &sync.Map{}, db.NamingStrategy)
+			stmt := &gorm.Statement{DB: db, Table: user.Table, Schema: user, Clauses: map[string]clause.Clause{}}
+			result.Case.Build(stmt)
+			if stmt.SQL.String() != result.Result {
+				t.Errorf("generated SQL is not equal, expects %v, but got %v", result.Result, stmt.SQL.String())
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("generated vars is not equal, expects %v, but got %v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+
+	checkBuildClauses(t, []clause.Interface{
+		clause.Update{},
+		clause.Set{{Column: clause.Column{Name: "name"}, Value: clause.Case{
+			Value: clause.Column{Name: "id"},
+			Whens: []clause.When{{Cond: 1, Then: "jinzhu"}, {Cond: 2, Then: "jinzhu2"}},
+			Else:  clause.Column{Name: "name"},
+		}}},
+	}, "UPDATE `users` SET `name`=CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `name` END", []interface{}{1, "jinzhu", 2, "jinzhu2"})
+}
@@ -2,6 +2,7 @@ package clause
 
 type Delete struct {
 	Modifier string
+	Tables   []Table
 }
 
 func (d Delete) Name() string {
@@ -15,9 +16,26 @@ func (d Delete) Build(builder Builder) {
 		builder.WriteByte(' ')
 		builder.WriteString(d.Modifier)
 	}
+
+	for idx, table := range d.Tables {
+		if idx > 0 {
+			builder.WriteByte(',')
+		} else {
+			builder.WriteByte(' ')
+		}
+		builder.WriteQuoted(table)
+	}
 }
 
 func (d Delete) MergeClause(clause *Clause) {
 	clause.Name = ""
+	if v, ok := clause.Expression.(Delete); ok {
+		if d.Modifier == "" {
+			d.Modifier = v.Modifier
+		}
+		if len(d.Tables) == 0 {
+			d.Tables = v.Tables
+		}
+	}
 	clause.Expression = d
 }
@@ -21,6 +21,21 @@ func TestDelete(t *testing.T) {
 			[]clause.Interface{clause.Delete{Modifier: "LOW_PRIORITY"}, clause.From{}},
 			"DELETE LOW_PRIORITY FROM `users`", nil,
 		},
+		{
+			[]clause.Interface{
+				clause.Delete{Tables: []clause.Table{{Name: clause.CurrentTable}}},
+				clause.From{Joins: []clause.Join{{Table: clause.Table{Name: "companies"}, Using: []string{"company_id"}}}},
+			},
+			"DELETE `users` FROM `users` JOIN `companies` USING (`company_id`)", nil,
+		},
+		{
+			[]clause.Interface{
+				clause.Delete{}, clause.From{},
+				clause.Using{Tables: []clause.Table{{Name: "companies", Alias: "c"}}},
+				clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "c", Name: "id"}, Value: clause.Column{Table: clause.CurrentTable, Name: "company_id"}}}},
+			},
+			"DELETE FROM `users` USING `companies` `c` WHERE `c`.`id` = `users`.`company_id`", nil,
+		},
 	}
 
 	for idx, result := range results {
@@ -0,0 +1,129 @@
+package clause
+
+// Merge merge clause, merges rows of Using into Table, Using could be a table, values, subquery or expression, Alias
+// names the source, it is required for sources other than tables
+//
+//	MERGE INTO `users` USING `staging_users` `s` ON `users`.`id` = `s`.`id`
+//	WHEN MATCHED AND `s`.`deleted` = ? THEN DELETE
+//	WHEN MATCHED THEN UPDATE SET `name`=`s`.`name`
+//	WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`s`.`id`,`s`.`name`)
+type Merge struct {
+	Table Table
+	Using interface{}
+	Alias string
+	On    []Expression
+	Whens []MergeWhen
+}
+
+// MergeWhen when branch of merge clause, Then is executed for rows matched or not matched by On and Conds, it should
+// be MergeUpdate, MergeDelete or MergeInsert
+type MergeWhen struct {
+	Matched bool
+	Conds   []Expression
+	Then    Expression
+}
+
+// MergeUpdate updates matched rows with Set
+type MergeUpdate struct {
+	Set Set
+}
+
+// MergeDelete deletes matched rows
+type MergeDelete struct{}
+
+// MergeInsert inserts Values for not matched rows
+type MergeInsert struct {
+	Values Values
+}
+
+// Name merge clause name
+func (merge Merge) Name() string {
+	return "MERGE"
+}
+
+// Build build merge clause
+func (merge Merge) Build(builder Builder) {
+	builder.WriteString("INTO ")
+	if merge.Table.Name == "" {
+		builder.WriteQuoted(currentTable)
+	} else {
+		builder.WriteQuoted(merge.Table)
+	}
+
+	builder.WriteString(" USING ")
+	switch source := merge.Using.(type) {
+	case Table:
+		if merge.Alias != "" {
+			source.Alias = merge.Alias
+		}
+		builder.WriteQuoted(source)
+	case Values:
+		builder.WriteString("(VALUES ")
+		for idx, value := range source.Values {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteByte('(')
+			builder.AddVar(builder, value...)
+			builder.WriteByte(')')
+		}
+		builder.WriteString(") AS ")
+		builder.WriteQuoted(merge.Alias)
+		if len(source.Columns) > 0 {
+			builder.WriteString(" (")
+			for idx, column := range source.Columns {
+				if idx > 0 {
+					builder.WriteByte(',')
+				}
+				builder.WriteQuoted(column.Name)
+			}
+			builder.WriteByte(')')
+		}
+	default:
+		builder.WriteByte('(')
+		builder.AddVar(builder, source)
+		builder.WriteString(") AS ")
+		builder.WriteQuoted(merge.Alias)
+	}
+
+	builder.WriteString(" ON ")
+	Where{Exprs: merge.On}.Build(builder)
+
+	for _, when := range merge.Whens {
+		if when.Matched {
+			builder.WriteString(" WHEN MATCHED")
+		} else {
+			builder.WriteString(" WHEN NOT MATCHED")
+		}
+
+		if len(when.Conds) > 0 {
+			builder.WriteString(" AND ")
+			Where{Exprs: when.Conds}.Build(builder)
+		}
+
+		builder.WriteString(" THEN ")
+		when.Then.Build(builder)
+	}
+}
+
+// MergeClause merge merge clauses
+func (merge Merge) MergeClause(clause *Clause) {
+	clause.Expression = merge
+}
+
+// Build build update action of merge clause
+func (update MergeUpdate) Build(builder Builder) {
+	builder.WriteString("UPDATE SET ")
+	update.Set.Build(builder)
+}
+
+// Build build delete action of merge clause
+func (MergeDelete) Build(builder Builder) {
+	builder.WriteString("DELETE")
+}
+
+// Build build insert action of merge clause
+func (insert MergeInsert) Build(builder Builder) {
+	builder.WriteString("INSERT ")
+	insert.Values.Build(builder)
+}
@@ -0,0 +1,62 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestMerge(t *testing.T) {
+	on := []clause.Expression{clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}, Value: clause.Column{Table: "s", Name: "id"}}}
+
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.Merge{
+				Using: clause.Table{Name: "staging_users"},
+				Alias: "s",
+				On:    on,
+				Whens: []clause.MergeWhen{
+					{Matched: true, Conds: []clause.Expression{clause.Eq{Column: clause.Column{Table: "s", Name: "active"}, Value: false}}, Then: clause.MergeDelete{}},
+					{Matched: true, Then: clause.MergeUpdate{Set: clause.Set{{Column: clause.Column{Name: "name"}, Value: clause.Column{Table: "s", Name: "name"}}}}},
+					{Then: clause.MergeInsert{Values: clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{clause.Column{Table: "s", Name: "id"}, clause.Column{Table: "s", Name: "name"}}}}}},
+				},
+			}},
+			"MERGE INTO `users` USING `staging_users` `s` ON `users`.`id` = `s`.`id` WHEN MATCHED AND `s`.`active` = ? THEN DELETE WHEN MATCHED THEN UPDATE SET `name`=`s`.`name` WHEN NOT MATCHED THEN INSERT (`id`,`name`) VALUES (`s`.`id`,`s`.`name`)",
+			[]interface{}{false},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Table: clause.Table{Name: "accounts"},
+				Using: clause.Values{Columns: []clause.Column{{Name: "id"}, {Name: "name"}}, Values: [][]interface{}{{1, "jinzhu"}, {2, "jinzhu2"}}},
+				Alias: "s",
+				On:    []clause.Expression{clause.Expr{SQL: "accounts.id = s.id"}},
+				Whens: []clause.MergeWhen{
+					{Matched: true, Then: clause.MergeUpdate{Set: clause.Set{{Column: clause.Column{Name: "name"}, Value: clause.Column{Table: "s", Name: "name"}}}}},
+				},
+			}},
+			"MERGE INTO `accounts` USING (VALUES (?,?),(?,?)) AS `s` (`id`,`name`) ON accounts.id = s.id WHEN MATCHED THEN UPDATE SET `name`=`s`.`name`",
+			[]interface{}{1, "jinzhu", 2, "jinzhu2"},
+		},
+		{
+			[]clause.Interface{clause.Merge{
+				Using: clause.Expr{SQL: "SELECT * FROM staging_users WHERE age > ?", Vars: []interface{}{18}},
+				Alias: "s",
+				On:    on,
+				Whens: []clause.MergeWhen{{Then: clause.MergeInsert{}}},
+			}},
+			"MERGE INTO `users` USING (SELECT * FROM staging_users WHERE age > ?) AS `s` ON `users`.`id` = `s`.`id` WHEN NOT MATCHED THEN INSERT DEFAULT VALUES",
+			[]interface{}{18},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
@@ -45,6 +45,12 @@ func (orderBy OrderBy) MergeClause(clause *Clause) {
 			}
 		}
 
+		// orders of expressions are combined with other orders in turn
+		if (orderBy.Expression != nil || v.Expression != nil) && (v.Expression != nil || len(v.Columns) > 0) {
+			clause.Expression = OrderBy{Expression: orderByExprs{v, orderBy}}
+			return
+		}
+
 		copiedColumns := make([]OrderByColumn, len(v.Columns))
 		copy(copiedColumns, v.Columns)
 		orderBy.Columns = append(copiedColumns, orderBy.Columns...)
@@ -52,3 +58,15 @@ func (orderBy OrderBy) MergeClause(clause *Clause) {
 
 	clause.Expression = orderBy
 }
+
+// orderByExprs combined orders
+type orderByExprs []Expression
+
+func (exprs orderByExprs) Build(builder Builder) {
+	for idx, expr := range exprs {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		expr.Build(builder)
+	}
+}
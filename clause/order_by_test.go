@@ -48,6 +48,29 @@ func TestOrderBy(t *testing.T) {
 			"SELECT * FROM `users` ORDER BY FIELD(id, ?,?,?)",
 			[]interface{}{1, 2, 3},
 		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}}},
+				}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "FIELD(id, ?)", Vars: []interface{}{[]int{1, 2, 3}}, WithoutParentheses: true},
+				}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}, Desc: true}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY `age`,FIELD(id, ?,?,?),`name` DESC",
+			[]interface{}{1, 2, 3},
+		},
+		{
+			[]clause.Interface{
+				clause.Select{}, clause.From{}, clause.OrderBy{
+					Expression: clause.Expr{SQL: "FIELD(id, ?)", Vars: []interface{}{[]int{1, 2, 3}}, WithoutParentheses: true},
+				}, clause.OrderBy{
+					Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "name"}, Reorder: true}},
+				},
+			},
+			"SELECT * FROM `users` ORDER BY `name`", nil,
+		},
 	}
 
 	for idx, result := range results {
@@ -0,0 +1,36 @@
+package clause
+
+// SetOperator set operator used to combine queries
+type SetOperator string
+
+const (
+	Union     SetOperator = "UNION"
+	UnionAll  SetOperator = "UNION ALL"
+	Intersect SetOperator = "INTERSECT"
+	Except    SetOperator = "EXCEPT"
+)
+
+// Of combine queries with the set operator
+func (operator SetOperator) Of(queries ...Expression) SetOperation {
+	return SetOperation{Operator: operator, Queries: queries}
+}
+
+// SetOperation combine the results of queries with a set operator
+//
+//	SELECT * FROM `users` WHERE age < ? UNION SELECT * FROM `users` WHERE age > ?
+type SetOperation struct {
+	Operator SetOperator
+	Queries  []Expression
+}
+
+// Build build set operation
+func (operation SetOperation) Build(builder Builder) {
+	for idx, query := range operation.Queries {
+		if idx > 0 {
+			builder.WriteByte(' ')
+			builder.WriteString(string(operation.Operator))
+			builder.WriteByte(' ')
+		}
+		query.Build(builder)
+	}
+}
@@ -0,0 +1,69 @@
+package clause_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestSetOperation(t *testing.T) {
+	results := []struct {
+		Operation clause.SetOperation
+		Result    string
+		Vars      []interface{}
+	}{
+		{
+			Operation: clause.Union.Of(
+				clause.Expr{SQL: "SELECT * FROM `users` WHERE age < ?", Vars: []interface{}{18}},
+				clause.Expr{SQL: "SELECT * FROM `users` WHERE age > ?", Vars: []interface{}{60}},
+			),
+			Result: "SELECT * FROM `users` WHERE age < ? UNION SELECT * FROM `users` WHERE age > ?",
+			Vars:   []interface{}{18, 60},
+		},
+		{
+			Operation: clause.UnionAll.Of(
+				clause.Expr{SQL: "SELECT ?", Vars: []interface{}{1}},
+				clause.Expr{SQL: "SELECT ?", Vars: []interface{}{2}},
+				clause.Expr{SQL: "SELECT ?", Vars: []interface{}{3}},
+			),
+			Result: "SELECT ? UNION ALL SELECT ? UNION ALL SELECT ?",
+			Vars:   []interface{}{1, 2, 3},
+		},
+		{
+			Operation: clause.SetOperation{Operator: clause.Intersect, Queries: []clause.Expression{
+				clause.Expr{SQL: "SELECT id FROM `users`"},
+				clause.Expr{SQL: "SELECT user_id FROM `pets`"},
+			}},
+			Result: "SELECT id FROM `users` INTERSECT SELECT user_id FROM `pets`",
+		},
+		{
+			Operation: clause.Except.Of(
+				clause.Expr{SQL: "SELECT id FROM `users`"},
+				clause.Expr{SQL: "SELECT user_id FROM `pets` WHERE name = ?", Vars: []interface{}{"dog"}},
+			),
+			Result: "SELECT id FROM `users` EXCEPT SELECT user_id FROM `pets` WHERE name = ?",
+			Vars:   []interface{}{"dog"},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			user, _ := schema.Parse(&tests.User{}, &sync.Map{}, db.NamingStrategy)
+			stmt := &gorm.Statement{DB: db, Table: user.Table, Schema: user, Clauses: map[string]clause.Clause{}}
+			result.Operation.Build(stmt)
+			if stmt.SQL.String() != result.Result {
+				t.Errorf("generated SQL is not equal, expects %v, but got %v", result.Result, stmt.SQL.String())
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("generated vars is not equal, expects %v, but got %v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package clause
 type Update struct {
 	Modifier string
 	Table    Table
+	Joins    []Join
 }
 
 // Name update clause name
@@ -22,6 +23,11 @@ func (update Update) Build(builder Builder) {
 	} else {
 		builder.WriteQuoted(update.Table)
 	}
+
+	for _, join := range update.Joins {
+		builder.WriteByte(' ')
+		join.Build(builder)
+	}
 }
 
 // MergeClause merge update clause
@@ -33,6 +39,9 @@ func (update Update) MergeClause(clause *Clause) {
 		if update.Table.Name == "" {
 			update.Table = v.Table
 		}
+		if len(update.Joins) == 0 {
+			update.Joins = v.Joins
+		}
 	}
 	clause.Expression = update
 }
@@ -25,6 +25,14 @@ func TestUpdate(t *testing.T) {
 			[]clause.Interface{clause.Update{Table: clause.Table{Name: "products"}, Modifier: "LOW_PRIORITY"}},
 			"UPDATE LOW_PRIORITY `products`", nil,
 		},
+		{
+			[]clause.Interface{clause.Update{Joins: []clause.Join{{
+				Type:  clause.InnerJoin,
+				Table: clause.Table{Name: "companies"},
+				ON:    clause.Where{Exprs: []clause.Expression{clause.Eq{Column: clause.Column{Table: "companies", Name: "id"}, Value: clause.Column{Table: clause.CurrentTable, Name: "company_id"}}}},
+			}}}, clause.Set{{Column: clause.Column{Table: clause.CurrentTable, Name: "name"}, Value: "jinzhu"}}},
+			"UPDATE `users` INNER JOIN `companies` ON `companies`.`id` = `users`.`company_id` SET `users`.`name`=?", []interface{}{"jinzhu"},
+		},
 	}
 
 	for idx, result := range results {
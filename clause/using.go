@@ -0,0 +1,33 @@
+package clause
+
+// Using using clause, tables joined to the deleting table
+//
+//	DELETE FROM `users` USING `companies` `Company` WHERE ...
+type Using struct {
+	Tables []Table
+}
+
+// Name using clause name
+func (using Using) Name() string {
+	return "USING"
+}
+
+// Build build using clause
+func (using Using) Build(builder Builder) {
+	for idx, table := range using.Tables {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		builder.WriteQuoted(table)
+	}
+}
+
+// MergeClause merge using clauses
+func (using Using) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(Using); ok {
+		copiedTables := make([]Table, len(v.Tables))
+		copy(copiedTables, v.Tables)
+		using.Tables = append(copiedTables, using.Tables...)
+	}
+	clause.Expression = using
+}
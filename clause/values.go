@@ -1,8 +1,13 @@
 package clause
 
+// Values values clause, rows returned by Query are inserted instead of Values if Query is not nil
+//
+//	(`name`,`age`) VALUES (?,?),(?,?)
+//	(`name`,`age`) SELECT `name`,`age` FROM `users`
 type Values struct {
 	Columns []Column
 	Values  [][]interface{}
+	Query   Expression
 }
 
 // Name from clause name
@@ -22,6 +27,12 @@ func (values Values) Build(builder Builder) {
 		}
 		builder.WriteByte(')')
 
+		if values.Query != nil {
+			builder.WriteByte(' ')
+			values.Query.Build(builder)
+			return
+		}
+
 		builder.WriteString(" VALUES ")
 
 		for idx, value := range values.Values {
@@ -33,6 +44,8 @@ func (values Values) Build(builder Builder) {
 			builder.AddVar(builder, value...)
 			builder.WriteByte(')')
 		}
+	} else if values.Query != nil {
+		values.Query.Build(builder)
 	} else {
 		builder.WriteString("DEFAULT VALUES")
 	}
@@ -24,6 +24,26 @@ func TestValues(t *testing.T) {
 			"INSERT INTO `users` (`name`,`age`) VALUES (?,?),(?,?)",
 			[]interface{}{"jinzhu", 18, "josh", 1},
 		},
+		{
+			[]clause.Interface{
+				clause.Insert{Table: clause.Table{Name: "archives"}},
+				clause.Values{
+					Columns: []clause.Column{{Name: "name"}, {Name: "age"}},
+					Query:   clause.Expr{SQL: "SELECT `name`,`age` FROM `users` WHERE age > ?", Vars: []interface{}{60}},
+				},
+			},
+			"INSERT INTO `archives` (`name`,`age`) SELECT `name`,`age` FROM `users` WHERE age > ?",
+			[]interface{}{60},
+		},
+		{
+			[]clause.Interface{
+				clause.Insert{Table: clause.Table{Name: "archives"}},
+				clause.Values{Query: clause.Expr{SQL: "SELECT * FROM `users`"}},
+				clause.Returning{Columns: []clause.Column{{Name: "id"}}},
+			},
+			"INSERT INTO `archives` SELECT * FROM `users` RETURNING `id`",
+			nil,
+		},
 	}
 
 	for idx, result := range results {
@@ -0,0 +1,166 @@
+package clause
+
+import "strconv"
+
+// Over window function expression
+//
+//	ROW_NUMBER() OVER (PARTITION BY `user_id` ORDER BY `created_at` DESC)
+type Over struct {
+	Function Expression
+	Window   Window
+}
+
+// Build build window function expression
+func (over Over) Build(builder Builder) {
+	if over.Function != nil {
+		over.Function.Build(builder)
+		builder.WriteByte(' ')
+	}
+
+	builder.WriteString("OVER (")
+	over.Window.Build(builder)
+	builder.WriteByte(')')
+}
+
+// Window window specification
+type Window struct {
+	PartitionBy []Column
+	OrderBy     OrderBy
+	Frame       *Frame
+}
+
+// Build build window specification
+func (window Window) Build(builder Builder) {
+	if len(window.PartitionBy) > 0 {
+		builder.WriteString("PARTITION BY ")
+		for idx, column := range window.PartitionBy {
+			if idx > 0 {
+				builder.WriteByte(',')
+			}
+			builder.WriteQuoted(column)
+		}
+	}
+
+	if len(window.OrderBy.Columns) > 0 || window.OrderBy.Expression != nil {
+		if len(window.PartitionBy) > 0 {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString("ORDER BY ")
+		window.OrderBy.Build(builder)
+	}
+
+	if window.Frame != nil {
+		if len(window.PartitionBy) > 0 || len(window.OrderBy.Columns) > 0 || window.OrderBy.Expression != nil {
+			builder.WriteByte(' ')
+		}
+		window.Frame.Build(builder)
+	}
+}
+
+// FrameUnit unit of window frame
+type FrameUnit string
+
+const (
+	FrameRows   FrameUnit = "ROWS"
+	FrameRange  FrameUnit = "RANGE"
+	FrameGroups FrameUnit = "GROUPS"
+)
+
+// Frame window frame, the frame only has a start bound if End is nil
+//
+//	ROWS BETWEEN 2 PRECEDING AND CURRENT ROW
+type Frame struct {
+	Unit  FrameUnit
+	Start FrameBound
+	End   *FrameBound
+}
+
+// Build build window frame
+func (frame Frame) Build(builder Builder) {
+	if frame.Unit == "" {
+		builder.WriteString(string(FrameRows))
+	} else {
+		builder.WriteString(string(frame.Unit))
+	}
+
+	if frame.End != nil {
+		builder.WriteString(" BETWEEN ")
+		frame.Start.Build(builder)
+		builder.WriteString(" AND ")
+		frame.End.Build(builder)
+	} else {
+		builder.WriteByte(' ')
+		frame.Start.Build(builder)
+	}
+}
+
+// FrameBoundType type of window frame bound
+type FrameBoundType string
+
+const (
+	FrameUnboundedPreceding FrameBoundType = "UNBOUNDED PRECEDING"
+	FramePreceding          FrameBoundType = "PRECEDING"
+	FrameCurrentRow         FrameBoundType = "CURRENT ROW"
+	FrameFollowing          FrameBoundType = "FOLLOWING"
+	FrameUnboundedFollowing FrameBoundType = "UNBOUNDED FOLLOWING"
+)
+
+// FrameBound window frame bound, Offset is used for PRECEDING and FOLLOWING
+type FrameBound struct {
+	Type   FrameBoundType
+	Offset int
+}
+
+// Build build window frame bound
+func (bound FrameBound) Build(builder Builder) {
+	switch bound.Type {
+	case FramePreceding, FrameFollowing:
+		builder.WriteString(strconv.Itoa(bound.Offset))
+		builder.WriteByte(' ')
+		builder.WriteString(string(bound.Type))
+	case "":
+		builder.WriteString(string(FrameCurrentRow))
+	default:
+		builder.WriteString(string(bound.Type))
+	}
+}
+
+// RowNumber ROW_NUMBER() OVER window
+func RowNumber(window Window) Over {
+	return Over{Function: Expr{SQL: "ROW_NUMBER()"}, Window: window}
+}
+
+// Rank RANK() OVER window
+func Rank(window Window) Over {
+	return Over{Function: Expr{SQL: "RANK()"}, Window: window}
+}
+
+// DenseRank DENSE_RANK() OVER window
+func DenseRank(window Window) Over {
+	return Over{Function: Expr{SQL: "DENSE_RANK()"}, Window: window}
+}
+
+// Lag LAG(column, offset[, default]) OVER window, value of the row offset rows before the current row
+func Lag(column Column, offset int, window Window, defaultValue ...interface{}) Over {
+	return Over{Function: offsetFunction("LAG", column, offset, defaultValue), Window: window}
+}
+
+// Lead LEAD(column, offset[, default]) OVER window, value of the row offset rows after the current row
+func Lead(column Column, offset int, window Window, defaultValue ...interface{}) Over {
+	return Over{Function: offsetFunction("LEAD", column, offset, defaultValue), Window: window}
+}
+
+// Sum SUM(column) OVER window
+func Sum(column Column, window Window) Over {
+	return Over{Function: Expr{SQL: "SUM(?)", Vars: []interface{}{column}}, Window: window}
+}
+
+func offsetFunction(name string, column Column, offset int, defaultValue []interface{}) Expr {
+	expr := Expr{SQL: name + "(?, " + strconv.Itoa(offset), Vars: []interface{}{column}}
+	if len(defaultValue) > 0 {
+		expr.SQL += ", ?"
+		expr.Vars = append(expr.Vars, defaultValue[0])
+	}
+	expr.SQL += ")"
+	return expr
+}
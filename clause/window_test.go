@@ -0,0 +1,104 @@
+package clause_test
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils/tests"
+)
+
+func TestWindow(t *testing.T) {
+	results := []struct {
+		Expression clause.Expression
+		Result     string
+		Vars       []interface{}
+	}{
+		{
+			Expression: clause.RowNumber(clause.Window{}),
+			Result:     "ROW_NUMBER() OVER ()",
+		},
+		{
+			Expression: clause.RowNumber(clause.Window{
+				PartitionBy: []clause.Column{{Name: "company_id"}, {Name: "manager_id"}},
+				OrderBy:     clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}, Desc: true}}},
+			}),
+			Result: "ROW_NUMBER() OVER (PARTITION BY `company_id`,`manager_id` ORDER BY `age` DESC)",
+		},
+		{
+			Expression: clause.Rank(clause.Window{
+				OrderBy: clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.PrimaryColumn}}},
+			}),
+			Result: "RANK() OVER (ORDER BY `users`.`id`)",
+		},
+		{
+			Expression: clause.DenseRank(clause.Window{
+				PartitionBy: []clause.Column{{Table: clause.CurrentTable, Name: "company_id"}},
+				OrderBy:     clause.OrderBy{Expression: clause.Expr{SQL: "age % ?", Vars: []interface{}{10}}},
+			}),
+			Result: "DENSE_RANK() OVER (PARTITION BY `users`.`company_id` ORDER BY age % ?)",
+			Vars:   []interface{}{10},
+		},
+		{
+			Expression: clause.Lag(clause.Column{Name: "age"}, 1, clause.Window{
+				OrderBy: clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "birthday"}}}},
+			}),
+			Result: "LAG(`age`, 1) OVER (ORDER BY `birthday`)",
+		},
+		{
+			Expression: clause.Lead(clause.Column{Name: "age"}, 2, clause.Window{
+				PartitionBy: []clause.Column{{Name: "company_id"}},
+			}, 0),
+			Result: "LEAD(`age`, 2, ?) OVER (PARTITION BY `company_id`)",
+			Vars:   []interface{}{0},
+		},
+		{
+			Expression: clause.Sum(clause.Column{Name: "age"}, clause.Window{
+				PartitionBy: []clause.Column{{Name: "company_id"}},
+				OrderBy:     clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "id"}}}},
+				Frame: &clause.Frame{
+					Start: clause.FrameBound{Type: clause.FrameUnboundedPreceding},
+					End:   &clause.FrameBound{Type: clause.FrameCurrentRow},
+				},
+			}),
+			Result: "SUM(`age`) OVER (PARTITION BY `company_id` ORDER BY `id` ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW)",
+		},
+		{
+			Expression: clause.Sum(clause.Column{Name: "age"}, clause.Window{
+				OrderBy: clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "id"}}}},
+				Frame: &clause.Frame{
+					Unit:  clause.FrameRange,
+					Start: clause.FrameBound{Type: clause.FramePreceding, Offset: 2},
+					End:   &clause.FrameBound{Type: clause.FrameFollowing, Offset: 3},
+				},
+			}),
+			Result: "SUM(`age`) OVER (ORDER BY `id` RANGE BETWEEN 2 PRECEDING AND 3 FOLLOWING)",
+		},
+		{
+			Expression: clause.Over{
+				Function: clause.Expr{SQL: "AVG(?)", Vars: []interface{}{clause.Column{Name: "age"}}},
+				Window:   clause.Window{Frame: &clause.Frame{Unit: clause.FrameGroups, Start: clause.FrameBound{Type: clause.FramePreceding, Offset: 1}}},
+			},
+			Result: "AVG(`age`) OVER (GROUPS 1 PRECEDING)",
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			user, _ := schema.Parse(&tests.User{}, &sync.Map{}, db.NamingStrategy)
+			stmt := &gorm.Statement{DB: db, Table: user.Table, Schema: user, Clauses: map[string]clause.Clause{}}
+			result.Expression.Build(stmt)
+			if stmt.SQL.String() != result.Result {
+				t.Errorf("generated SQL is not equal, expects %v, but got %v", result.Result, stmt.SQL.String())
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, result.Vars) {
+				t.Errorf("generated vars is not equal, expects %v, but got %v", result.Vars, stmt.Vars)
+			}
+		})
+	}
+}
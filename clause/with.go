@@ -1,3 +1,79 @@
 package clause
 
-type With struct{}
+// With with clause, renders common table expressions ahead of the statement
+//
+//	WITH RECURSIVE `tree` (`id`,`parent_id`) AS (SELECT ...) SELECT * FROM `tree`
+type With struct {
+	Recursive bool
+	CTEs      []CTE
+}
+
+// CTE common table expression
+type CTE struct {
+	Name     string
+	Columns  []string
+	Subquery Expression
+}
+
+// Name with clause name
+func (with With) Name() string {
+	return "WITH"
+}
+
+// Build build with clause
+func (with With) Build(builder Builder) {
+	if with.Recursive {
+		builder.WriteString("RECURSIVE ")
+	}
+
+	for idx, cte := range with.CTEs {
+		if idx > 0 {
+			builder.WriteByte(',')
+		}
+		cte.Build(builder)
+	}
+}
+
+// MergeClause merge with clauses
+func (with With) MergeClause(clause *Clause) {
+	if v, ok := clause.Expression.(With); ok {
+		copiedCTEs := make([]CTE, 0, len(v.CTEs)+len(with.CTEs))
+		copiedCTEs = append(copiedCTEs, v.CTEs...)
+
+		// a later CTE with the same name replaces the earlier one
+		for _, cte := range with.CTEs {
+			replaced := false
+			for idx, c := range copiedCTEs {
+				if c.Name == cte.Name {
+					copiedCTEs[idx] = cte
+					replaced = true
+					break
+				}
+			}
+
+			if !replaced {
+				copiedCTEs = append(copiedCTEs, cte)
+			}
+		}
+
+		with.CTEs = copiedCTEs
+		with.Recursive = with.Recursive || v.Recursive
+	}
+
+	clause.Expression = with
+}
+
+// Build build common table expression
+func (cte CTE) Build(builder Builder) {
+	builder.WriteQuoted(cte.Name)
+	if len(cte.Columns) > 0 {
+		builder.WriteByte(' ')
+		builder.WriteQuoted(cte.Columns)
+	}
+
+	builder.WriteString(" AS (")
+	if cte.Subquery != nil {
+		cte.Subquery.Build(builder)
+	}
+	builder.WriteByte(')')
+}
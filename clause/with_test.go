@@ -0,0 +1,58 @@
+package clause_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm/clause"
+)
+
+func TestWith(t *testing.T) {
+	results := []struct {
+		Clauses []clause.Interface
+		Result  string
+		Vars    []interface{}
+	}{
+		{
+			[]clause.Interface{clause.With{CTEs: []clause.CTE{{
+				Name:     "adults",
+				Subquery: clause.Expr{SQL: "SELECT * FROM `users` WHERE age >= ?", Vars: []interface{}{18}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "adults"}}}},
+			"WITH `adults` AS (SELECT * FROM `users` WHERE age >= ?) SELECT * FROM `adults`",
+			[]interface{}{18},
+		},
+		{
+			[]clause.Interface{clause.With{Recursive: true, CTEs: []clause.CTE{{
+				Name:     "tree",
+				Columns:  []string{"id", "parent_id"},
+				Subquery: clause.Expr{SQL: "SELECT id, parent_id FROM categories WHERE id = ? UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id", Vars: []interface{}{1}},
+			}}}, clause.Select{}, clause.From{Tables: []clause.Table{{Name: "tree"}}}},
+			"WITH RECURSIVE `tree` (`id`,`parent_id`) AS (SELECT id, parent_id FROM categories WHERE id = ? UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree ON c.parent_id = tree.id) SELECT * FROM `tree`",
+			[]interface{}{1},
+		},
+		{
+			[]clause.Interface{
+				clause.With{CTEs: []clause.CTE{{Name: "a", Subquery: clause.Expr{SQL: "SELECT ?", Vars: []interface{}{1}}}}},
+				clause.With{Recursive: true, CTEs: []clause.CTE{{Name: "b", Subquery: clause.Expr{SQL: "SELECT ?", Vars: []interface{}{2}}}}},
+				clause.With{CTEs: []clause.CTE{{Name: "a", Subquery: clause.Expr{SQL: "SELECT ?", Vars: []interface{}{3}}}}},
+				clause.Select{}, clause.From{},
+			},
+			"WITH RECURSIVE `a` AS (SELECT ?),`b` AS (SELECT ?) SELECT * FROM `users`",
+			[]interface{}{3, 2},
+		},
+		{
+			[]clause.Interface{
+				clause.With{CTEs: []clause.CTE{{Name: "inactive", Subquery: clause.Expr{SQL: "SELECT id FROM `users` WHERE active = ?", Vars: []interface{}{false}}}}},
+				clause.Delete{}, clause.From{}, clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "id IN (SELECT id FROM inactive)"}}},
+			},
+			"WITH `inactive` AS (SELECT id FROM `users` WHERE active = ?) DELETE FROM `users` WHERE id IN (SELECT id FROM inactive)",
+			[]interface{}{false},
+		},
+	}
+
+	for idx, result := range results {
+		t.Run(fmt.Sprintf("case #%v", idx), func(t *testing.T) {
+			checkBuildClauses(t, result.Clauses, result.Result, result.Vars)
+		})
+	}
+}
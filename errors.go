@@ -45,10 +45,18 @@ var (
 	ErrInvalidValueOfLength = errors.New("invalid association values, length doesn't match")
 	// ErrPreloadNotAllowed preload is not allowed when count is used
 	ErrPreloadNotAllowed = errors.New("preload is not allowed when count is used")
+	// ErrInvalidCursor invalid pagination cursor
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrOptimisticLockConflict record is changed by others since its version is read
+	ErrOptimisticLockConflict = errors.New("optimistic lock conflict")
 	// ErrDuplicatedKey occurs when there is a unique key constraint violation
 	ErrDuplicatedKey = errors.New("duplicated key not allowed")
 	// ErrForeignKeyViolated occurs when there is a foreign key constraint violation
 	ErrForeignKeyViolated = errors.New("violates foreign key constraint")
 	// ErrCheckConstraintViolated occurs when there is a check constraint violation
 	ErrCheckConstraintViolated = errors.New("violates check constraint")
+	// ErrSerializationFailure occurs when a transaction could not be serialized with concurrent transactions
+	ErrSerializationFailure = errors.New("could not serialize access due to concurrent update")
+	// ErrDeadlock occurs when a transaction is chosen as the victim of a deadlock
+	ErrDeadlock = errors.New("deadlock detected")
 )
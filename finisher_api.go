@@ -71,6 +71,37 @@ func (db *DB) CreateInBatches(value interface{}, batchSize int) (tx *DB) {
 	return
 }
 
+// CreateFrom inserts the rows returned by query into the current table, query could be *gorm.DB or clause.Expression,
+// columns are the inserting columns matching the selected columns of query
+//
+// Rows are not loaded into Go, so hooks are skipped, use clause.Returning with a slice model to
+// receive the inserted rows
+//
+//	db.Model(&Archive{}).CreateFrom(db.Model(&User{}).Select("id", "name").Where("age > ?", 60), "user_id", "name")
+//	// INSERT INTO `archives` (`user_id`,`name`) SELECT `id`,`name` FROM `users` WHERE age > 60 AND `users`.`deleted_at` IS NULL
+func (db *DB) CreateFrom(query interface{}, columns ...string) (tx *DB) {
+	tx = db.getInstance()
+
+	values := clause.Values{Columns: make([]clause.Column, 0, len(columns))}
+	for _, column := range columns {
+		values.Columns = append(values.Columns, clause.Column{Name: column})
+	}
+
+	switch v := query.(type) {
+	case clause.Expression:
+		values.Query = v
+	case nil:
+		tx.AddError(fmt.Errorf("%w for insert from query", ErrSubQueryRequired))
+		return
+	default:
+		values.Query = clause.Expr{SQL: "?", Vars: []interface{}{query}}
+	}
+
+	tx.Statement.AddClause(values)
+	tx.Statement.SkipHooks = true
+	return tx.callbacks.Create().Execute(tx)
+}
+
 // Save updates value in database. If value doesn't contain a matching primary key, value is inserted.
 func (db *DB) Save(value interface{}) (tx *DB) {
 	tx = db.getInstance()
@@ -619,42 +650,66 @@ func (db *DB) Connection(fc func(tx *DB) error) (err error) {
 // Transaction start a transaction as a block, return error will rollback, otherwise to commit. Transaction executes an
 // arbitrary number of commands in fc within a transaction. On success the changes are committed; if an error occurs
 // they are rolled back.
+//
+// With a TransactionRetry policy, the transaction is started over and fc is called again if it fails with a retryable
+// error, nested transactions are never retried on their own as their outermost transaction has to start over
 func (db *DB) Transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
-	panicked := true
-
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
-		// nested transaction
-		if !db.DisableNestedTransaction {
-			spID := new(maphash.Hash).Sum64()
-			err = db.SavePoint(fmt.Sprintf("sp%d", spID)).Error
-			if err != nil {
-				return
-			}
-			defer func() {
-				// Make sure to rollback when panic, Block error or Commit error
-				if panicked || err != nil {
-					db.RollbackTo(fmt.Sprintf("sp%d", spID))
-				}
-			}()
+		return db.nestedTransaction(fc)
+	}
+
+	retry := db.TransactionRetry
+	for attempt := 1; ; attempt++ {
+		if err = db.transaction(fc, opts...); err == nil || retry == nil || attempt >= retry.MaxAttempts || !retry.retryable(db, err) {
+			return err
 		}
-		err = fc(db.Session(&Session{NewDB: db.clone == 1}))
-	} else {
-		tx := db.Begin(opts...)
-		if tx.Error != nil {
-			return tx.Error
+
+		if retry.wait(db.Statement.Context, attempt) != nil {
+			return err
 		}
+	}
+}
 
+func (db *DB) nestedTransaction(fc func(tx *DB) error) (err error) {
+	panicked := true
+
+	if !db.DisableNestedTransaction {
+		spID := new(maphash.Hash).Sum64()
+		err = db.SavePoint(fmt.Sprintf("sp%d", spID)).Error
+		if err != nil {
+			return
+		}
 		defer func() {
 			// Make sure to rollback when panic, Block error or Commit error
 			if panicked || err != nil {
-				tx.Rollback()
+				db.RollbackTo(fmt.Sprintf("sp%d", spID))
 			}
 		}()
+	}
+	err = fc(db.Session(&Session{NewDB: db.clone == 1}))
+
+	panicked = false
+	return
+}
+
+func (db *DB) transaction(fc func(tx *DB) error, opts ...*sql.TxOptions) (err error) {
+	panicked := true
+
+	tx := db.Begin(opts...)
+	if tx.Error != nil {
+		return tx.Error
+	}
 
-		if err = fc(tx); err == nil {
-			panicked = false
-			return tx.Commit().Error
+	defer func() {
+		// Make sure to rollback when panic, Block error or Commit error
+		if panicked || err != nil {
+			tx.Rollback()
 		}
+	}()
+
+	if err = fc(tx); err == nil {
+		panicked = false
+		return tx.Commit().Error
 	}
 
 	panicked = false
@@ -700,7 +755,9 @@ func (db *DB) Begin(opts ...*sql.TxOptions) *DB {
 // Commit commits the changes in a transaction
 func (db *DB) Commit() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil && !reflect.ValueOf(committer).IsNil() {
-		db.AddError(committer.Commit())
+		err := committer.Commit()
+		db.AddError(err)
+		db.completeTransaction(err == nil)
 	} else {
 		db.AddError(ErrInvalidTransaction)
 	}
@@ -712,6 +769,7 @@ func (db *DB) Rollback() *DB {
 	if committer, ok := db.Statement.ConnPool.(TxCommitter); ok && committer != nil {
 		if !reflect.ValueOf(committer).IsNil() {
 			db.AddError(committer.Rollback())
+			db.completeTransaction(false)
 		}
 	} else {
 		db.AddError(ErrInvalidTransaction)
@@ -731,7 +789,13 @@ func (db *DB) SavePoint(name string) *DB {
 		if preparedStmtTx, isPreparedStmtTx = db.Statement.ConnPool.(*PreparedStmtTX); isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx.Tx
 		}
-		db.AddError(savePointer.SavePoint(db, name))
+		err := savePointer.SavePoint(db, name)
+		if db.AddError(err); err == nil {
+			// savepoints are only tracked by transactions having hooks
+			if hooks, _ := db.transactionHooks(false); hooks != nil {
+				hooks.savePoint(name)
+			}
+		}
 		// restore prepared statement
 		if isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx
@@ -754,7 +818,12 @@ func (db *DB) RollbackTo(name string) *DB {
 		if preparedStmtTx, isPreparedStmtTx = db.Statement.ConnPool.(*PreparedStmtTX); isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx.Tx
 		}
-		db.AddError(savePointer.RollbackTo(db, name))
+		err := savePointer.RollbackTo(db, name)
+		if db.AddError(err); err == nil {
+			if hooks, _ := db.transactionHooks(false); hooks != nil {
+				hooks.rollbackTo(name)
+			}
+		}
 		// restore prepared statement
 		if isPreparedStmtTx {
 			db.Statement.ConnPool = preparedStmtTx
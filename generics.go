@@ -52,6 +52,8 @@ type CreateInterface[T any] interface {
 	Offset(offset int) ChainInterface[T]
 	Joins(query clause.JoinTarget, on func(db JoinBuilder, joinTable clause.Table, curTable clause.Table) error) ChainInterface[T]
 	Preload(association string, query func(db PreloadBuilder) error) ChainInterface[T]
+	With(name string, subquery interface{}, columns ...string) ChainInterface[T]
+	WithRecursive(name string, subquery interface{}, columns ...string) ChainInterface[T]
 	Select(query string, args ...interface{}) CreateInterface[T]
 	Omit(columns ...string) CreateInterface[T]
 	MapColumns(m map[string]string) ChainInterface[T]
@@ -69,6 +71,7 @@ type CreateInterface[T any] interface {
 	Table(name string, args ...interface{}) CreateInterface[T]
 	Create(ctx context.Context, r *T) error
 	CreateInBatches(ctx context.Context, r *[]T, batchSize int) error
+	CreateFrom(ctx context.Context, query interface{}, columns ...string) (rowsAffected int, err error)
 	Set(assignments ...clause.Assigner) SetCreateOrUpdateInterface[T]
 }
 
@@ -82,6 +85,8 @@ type ChainInterface[T any] interface {
 	Offset(offset int) ChainInterface[T]
 	Joins(query clause.JoinTarget, on func(db JoinBuilder, joinTable clause.Table, curTable clause.Table) error) ChainInterface[T]
 	Preload(association string, query func(db PreloadBuilder) error) ChainInterface[T]
+	With(name string, subquery interface{}, columns ...string) ChainInterface[T]
+	WithRecursive(name string, subquery interface{}, columns ...string) ChainInterface[T]
 	Select(query string, args ...interface{}) ChainInterface[T]
 	Omit(columns ...string) ChainInterface[T]
 	MapColumns(m map[string]string) ChainInterface[T]
@@ -89,6 +94,10 @@ type ChainInterface[T any] interface {
 	Group(name string) ChainInterface[T]
 	Having(query interface{}, args ...interface{}) ChainInterface[T]
 	Order(value interface{}) ChainInterface[T]
+	Union(other ChainInterface[T]) ChainInterface[T]
+	UnionAll(other ChainInterface[T]) ChainInterface[T]
+	Intersect(other ChainInterface[T]) ChainInterface[T]
+	Except(other ChainInterface[T]) ChainInterface[T]
 	Set(assignments ...clause.Assigner) SetUpdateOnlyInterface[T]
 
 	Build(builder clause.Builder)
@@ -98,6 +107,7 @@ type ChainInterface[T any] interface {
 	Update(ctx context.Context, name string, value any) (rowsAffected int, err error)
 	Updates(ctx context.Context, t T) (rowsAffected int, err error)
 	Count(ctx context.Context, column string) (result int64, err error)
+	Page(ctx context.Context, cursor string, pageSize int) ([]T, Page, error)
 }
 
 // SetUpdateOnlyInterface is returned by Set after chaining; only Update is allowed
@@ -112,6 +122,7 @@ type SetCreateOrUpdateInterface[T any] interface {
 }
 
 type ExecInterface[T any] interface {
+	iterInterface[T]
 	Scan(ctx context.Context, r interface{}) error
 	First(context.Context) (T, error)
 	Last(ctx context.Context) (T, error)
@@ -231,6 +242,12 @@ func (c createG[T]) CreateInBatches(ctx context.Context, r *[]T, batchSize int)
 	return c.g.apply(ctx).CreateInBatches(r, batchSize).Error
 }
 
+func (c createG[T]) CreateFrom(ctx context.Context, query interface{}, columns ...string) (rowsAffected int, err error) {
+	var r T
+	res := c.g.apply(ctx).Model(&r).CreateFrom(query, columns...)
+	return int(res.RowsAffected), res.Error
+}
+
 type chainG[T any] struct {
 	execG[T]
 }
@@ -432,6 +449,18 @@ func (c chainG[T]) Joins(jt clause.JoinTarget, on func(db JoinBuilder, joinTable
 	})
 }
 
+func (c chainG[T]) With(name string, subquery interface{}, columns ...string) ChainInterface[T] {
+	return c.with(func(db *DB) *DB {
+		return db.With(name, subquery, columns...)
+	})
+}
+
+func (c chainG[T]) WithRecursive(name string, subquery interface{}, columns ...string) ChainInterface[T] {
+	return c.with(func(db *DB) *DB {
+		return db.WithRecursive(name, subquery, columns...)
+	})
+}
+
 func (c chainG[T]) Select(query string, args ...interface{}) ChainInterface[T] {
 	return c.with(func(db *DB) *DB {
 		return db.Select(query, args...)
@@ -478,6 +507,34 @@ func (c chainG[T]) Order(value interface{}) ChainInterface[T] {
 	})
 }
 
+func (c chainG[T]) Union(other ChainInterface[T]) ChainInterface[T] {
+	return c.setOperation(clause.Union, other)
+}
+
+func (c chainG[T]) UnionAll(other ChainInterface[T]) ChainInterface[T] {
+	return c.setOperation(clause.UnionAll, other)
+}
+
+func (c chainG[T]) Intersect(other ChainInterface[T]) ChainInterface[T] {
+	return c.setOperation(clause.Intersect, other)
+}
+
+func (c chainG[T]) Except(other ChainInterface[T]) ChainInterface[T] {
+	return c.setOperation(clause.Except, other)
+}
+
+// setOperation starts a new chain querying the combined results of the current chain and other
+func (c chainG[T]) setOperation(operator clause.SetOperator, other ChainInterface[T]) ChainInterface[T] {
+	return chainG[T]{
+		execG: execG[T]{g: &g[T]{
+			db: c.g.db,
+			ops: []op{func(db *DB) *DB {
+				return setOperation(db, operator, c, other)
+			}},
+		}},
+	}
+}
+
 func (c chainG[T]) Preload(association string, query func(db PreloadBuilder) error) ChainInterface[T] {
 	return c.with(func(db *DB) *DB {
 		return db.Preload(association, func(tx *DB) *DB {
@@ -531,24 +588,17 @@ func (c chainG[T]) Preload(association string, query func(db PreloadBuilder) err
 						selectExpr.Exprs = []clause.Expression{clause.Expr{SQL: "*", Vars: []interface{}{}}}
 					}
 
-					partitionBy := clause.CommaExpression{}
-					for _, column := range refColumns {
-						partitionBy.Exprs = append(partitionBy.Exprs, clause.Expr{SQL: "?", Vars: []interface{}{clause.Column{Name: column.Name}}})
-					}
-
-					rnnColumn := clause.Column{Name: "gorm_preload_rnn"}
-					sql := "ROW_NUMBER() OVER (PARTITION BY ? ?)"
-					vars := []interface{}{partitionBy}
-					if orderBy, ok := q.db.Statement.Clauses["ORDER BY"]; ok {
-						vars = append(vars, orderBy)
+					window := clause.Window{PartitionBy: refColumns}
+					if orderBy, ok := q.db.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy); ok {
+						window.OrderBy = orderBy
 					} else {
-						vars = append(vars, clause.Clause{Name: "ORDER BY", Expression: clause.OrderBy{
+						window.OrderBy = clause.OrderBy{
 							Columns: []clause.OrderByColumn{{Column: clause.PrimaryColumn, Desc: true}},
-						}})
+						}
 					}
-					vars = append(vars, rnnColumn)
 
-					selectExpr.Exprs = append(selectExpr.Exprs, clause.Expr{SQL: sql + " AS ?", Vars: vars})
+					rnnColumn := clause.Column{Name: "gorm_preload_rnn"}
+					selectExpr.Exprs = append(selectExpr.Exprs, clause.Expr{SQL: "? AS ?", Vars: []interface{}{clause.RowNumber(window), rnnColumn}})
 
 					q.db.Clauses(clause.Select{Expression: selectExpr})
 
@@ -584,6 +634,12 @@ func (c chainG[T]) Count(ctx context.Context, column string) (result int64, err
 	return
 }
 
+func (c chainG[T]) Page(ctx context.Context, cursor string, pageSize int) ([]T, Page, error) {
+	var r []T
+	page, err := c.g.apply(ctx).Paginate(&r, cursor, pageSize)
+	return r, page, err
+}
+
 func (c chainG[T]) Build(builder clause.Builder) {
 	subdb := c.getInstance()
 	subdb.Logger = logger.Discard
@@ -52,12 +52,18 @@ type Config struct {
 	IgnoreRelationshipsWhenMigrating bool
 	// DisableNestedTransaction disable nested transaction
 	DisableNestedTransaction bool
+	// TransactionRetry retry policy of transactions started with Transaction
+	TransactionRetry *RetryPolicy
+	// JoinContextTransaction sessions created with contexts carrying transactions of ContextWithTx join them
+	JoinContextTransaction bool
 	// AllowGlobalUpdate allow global update
 	AllowGlobalUpdate bool
 	// QueryFields executes the SQL query with all fields of the table
 	QueryFields bool
 	// CreateBatchSize default create batch size
 	CreateBatchSize int
+	// ConcurrentPreload max sibling preloads running concurrently out of transactions
+	ConcurrentPreload int
 	// TranslateError enabling error translation
 	TranslateError bool
 	// PropagateUnscoped propagate Unscoped to every other nested statement
@@ -74,6 +80,8 @@ type Config struct {
 
 	callbacks  *callbacks
 	cacheStore *sync.Map
+	// transactionHooksStore hooks of transactions by their connection pools
+	transactionHooksStore *sync.Map
 }
 
 // Apply update config to new config
@@ -128,6 +136,8 @@ type Session struct {
 	Logger                   logger.Interface
 	NowFunc                  func() time.Time
 	CreateBatchSize          int
+	TransactionRetry         *RetryPolicy
+	ConcurrentPreload        int
 }
 
 // Open initialize db session based on dialector
@@ -195,6 +205,10 @@ func Open(dialector Dialector, opts ...Option) (db *DB, err error) {
 		config.cacheStore = &sync.Map{}
 	}
 
+	if config.transactionHooksStore == nil {
+		config.transactionHooksStore = &sync.Map{}
+	}
+
 	db = &DB{Config: config, clone: 1}
 
 	db.callbacks = initializeCallbacks(db)
@@ -268,6 +282,10 @@ func (db *DB) Session(config *Session) *DB {
 		tx.Config.CreateBatchSize = config.CreateBatchSize
 	}
 
+	if config.ConcurrentPreload > 0 {
+		tx.Config.ConcurrentPreload = config.ConcurrentPreload
+	}
+
 	if config.SkipDefaultTransaction {
 		tx.Config.SkipDefaultTransaction = true
 	}
@@ -291,6 +309,9 @@ func (db *DB) Session(config *Session) *DB {
 
 	if config.Context != nil {
 		tx.Statement.Context = config.Context
+		if txConfig.JoinContextTransaction {
+			tx.joinContextTransaction()
+		}
 	}
 
 	if config.PrepareStmt {
@@ -328,6 +349,10 @@ func (db *DB) Session(config *Session) *DB {
 		txConfig.DisableNestedTransaction = true
 	}
 
+	if config.TransactionRetry != nil {
+		txConfig.TransactionRetry = config.TransactionRetry
+	}
+
 	if !config.NewDB {
 		tx.clone = 2
 	}
@@ -90,3 +90,11 @@ type Rows interface {
 type ErrorTranslator interface {
 	Translate(err error) error
 }
+
+// JSONAggregator dialector aggregating rows to JSON arrays, has many relations of Joins are loaded with JSON
+// aggregation subqueries instead of joined rows if the dialector implements it
+type JSONAggregator interface {
+	// JSONArrayAgg returns the aggregate expression of rows to a JSON array of objects keyed by names of columns,
+	// e.g. JSON_AGG(JSON_BUILD_OBJECT('id', "Pets"."id", 'name', "Pets"."name"))
+	JSONArrayAgg(columns []clause.Column) clause.Expression
+}
@@ -0,0 +1,70 @@
+//go:build go1.23
+
+package gorm
+
+import (
+	"context"
+	"iter"
+	"reflect"
+)
+
+// iterInterface streaming finisher of ExecInterface, only available since go1.23
+type iterInterface[T any] interface {
+	Iter(ctx context.Context) iter.Seq2[T, error]
+}
+
+// Iter iterates records one by one, every record is scanned into dest before it is yielded with its index, rows are
+// closed when the iteration stops
+//
+//	var user User
+//	for idx, err := range db.Where("age > ?", 18).Iter(&user) {
+//	  if err != nil {
+//	    return err
+//	  }
+//	  // user is the idx-th record
+//	}
+func (db *DB) Iter(dest interface{}) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		tx := db.getInstance()
+		if tx.Statement.Model == nil {
+			tx.Statement.Model = dest
+		}
+
+		rows, err := tx.Rows()
+		if err != nil {
+			yield(0, err)
+			return
+		}
+		defer rows.Close()
+
+		reflectValue := reflect.Indirect(reflect.ValueOf(dest))
+		for idx := 0; rows.Next(); idx++ {
+			if reflectValue.CanSet() {
+				reflectValue.Set(reflect.Zero(reflectValue.Type()))
+			}
+
+			if err = tx.ScanRows(rows, dest); err == nil && !tx.Statement.SkipHooks {
+				if i, ok := dest.(interface{ AfterFind(*DB) error }); ok {
+					err = i.AfterFind(tx.Session(&Session{NewDB: true}))
+				}
+			}
+
+			if !yield(idx, err) || err != nil {
+				return
+			}
+		}
+
+		if err = rows.Err(); err != nil {
+			yield(0, err)
+		}
+	}
+}
+
+func (g execG[T]) Iter(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var r T
+		g.g.apply(ctx).Iter(&r)(func(_ int, err error) bool {
+			return yield(r, err)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+//go:build !go1.23
+
+package gorm
+
+// iterInterface streaming finisher of ExecInterface, only available since go1.23
+type iterInterface[T any] interface{}
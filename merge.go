@@ -0,0 +1,203 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm/clause"
+)
+
+// Merger Merge Mode, builds a MERGE statement merging rows of source into the table of the statement, MERGE is not
+// supported by all databases, e.g. MySQL and SQLite
+//
+//	db.Model(&User{}).Merge("staging_users").As("s").On("users.id = s.id").
+//	  WhenMatchedDelete("s.deleted = ?", true).
+//	  WhenMatchedUpdate([]string{"name", "age"}).
+//	  WhenNotMatchedInsert([]string{"id", "name", "age"}).
+//	  Exec()
+//	// MERGE INTO `users` USING `staging_users` `s` ON users.id = s.id
+//	// WHEN MATCHED AND s.deleted = true THEN DELETE
+//	// WHEN MATCHED THEN UPDATE SET `name`=`s`.`name`,`age`=`s`.`age`
+//	// WHEN NOT MATCHED THEN INSERT (`id`,`name`,`age`) VALUES (`s`.`id`,`s`.`name`,`s`.`age`)
+type Merger struct {
+	DB     *DB
+	Source interface{}
+	Alias  string
+	Error  error
+	on     []clause.Expression
+	whens  []mergeWhen
+}
+
+type mergeWhen struct {
+	matched bool
+	conds   []interface{}
+	then    string
+	values  interface{}
+}
+
+// Merge merge source into the table of the statement, source could be a table name, clause.Table, clause.Values,
+// subquery or expression, sources other than tables are named "source" unless renamed with As
+func (db *DB) Merge(source interface{}) *Merger {
+	merger := &Merger{DB: db, Source: source}
+
+	switch v := source.(type) {
+	case string:
+		merger.Source = clause.Table{Name: v}
+	case nil:
+		merger.Error = fmt.Errorf("%w for merge", ErrSubQueryRequired)
+	}
+
+	return merger
+}
+
+// As specify the alias of the merge source
+func (merger *Merger) As(alias string) *Merger {
+	merger.Alias = alias
+	return merger
+}
+
+// On specify conditions matching rows of the source and the table, arguments are the same as Where
+func (merger *Merger) On(query interface{}, args ...interface{}) *Merger {
+	merger.on = append(merger.on, merger.DB.Statement.BuildCondition(query, args...)...)
+	return merger
+}
+
+// WhenMatchedUpdate update matched rows with values if conds match, values could be map[string]interface{},
+// clause.Set or column names that are copied from the source
+func (merger *Merger) WhenMatchedUpdate(values interface{}, conds ...interface{}) *Merger {
+	merger.whens = append(merger.whens, mergeWhen{matched: true, conds: conds, then: "UPDATE", values: values})
+	return merger
+}
+
+// WhenMatchedDelete delete matched rows if conds match
+func (merger *Merger) WhenMatchedDelete(conds ...interface{}) *Merger {
+	merger.whens = append(merger.whens, mergeWhen{matched: true, conds: conds, then: "DELETE"})
+	return merger
+}
+
+// WhenNotMatchedInsert insert values for not matched rows if conds match, values could be map[string]interface{},
+// clause.Values or column names that are copied from the source
+func (merger *Merger) WhenNotMatchedInsert(values interface{}, conds ...interface{}) *Merger {
+	merger.whens = append(merger.whens, mergeWhen{conds: conds, then: "INSERT", values: values})
+	return merger
+}
+
+// Exec execute the merge statement
+func (merger *Merger) Exec() (tx *DB) {
+	tx = merger.DB.getInstance()
+	if merger.Error != nil {
+		tx.AddError(merger.Error)
+		return
+	}
+
+	if len(merger.on) == 0 {
+		tx.AddError(fmt.Errorf("%w: merge conditions required, use On to specify them", ErrMissingWhereClause))
+		return
+	}
+
+	if len(merger.whens) == 0 {
+		tx.AddError(fmt.Errorf("%w: merge actions required, use WhenMatchedUpdate, WhenMatchedDelete or WhenNotMatchedInsert to specify them", ErrInvalidData))
+		return
+	}
+
+	stmt := tx.Statement
+	if stmt.Model != nil {
+		if err := stmt.Parse(stmt.Model); err != nil {
+			tx.AddError(err)
+			return
+		}
+	}
+
+	mergeClause := clause.Merge{Using: merger.Source, Alias: merger.Alias, On: merger.on}
+	sourceName := merger.Alias
+	if table, ok := merger.Source.(clause.Table); ok {
+		if sourceName == "" {
+			if sourceName = table.Alias; sourceName == "" {
+				sourceName = table.Name
+			}
+		}
+	} else if sourceName == "" {
+		sourceName = "source"
+		mergeClause.Alias = sourceName
+	}
+
+	for _, when := range merger.whens {
+		mergeWhen := clause.MergeWhen{Matched: when.matched}
+		if len(when.conds) > 0 {
+			mergeWhen.Conds = stmt.BuildCondition(when.conds[0], when.conds[1:]...)
+		}
+
+		switch when.then {
+		case "UPDATE":
+			mergeWhen.Then = clause.MergeUpdate{Set: mergeAssignments(tx, when.values, sourceName)}
+		case "DELETE":
+			mergeWhen.Then = clause.MergeDelete{}
+		case "INSERT":
+			mergeWhen.Then = clause.MergeInsert{Values: mergeValues(tx, when.values, sourceName)}
+		}
+
+		mergeClause.Whens = append(mergeClause.Whens, mergeWhen)
+	}
+
+	if tx.Error == nil {
+		stmt.SQL = strings.Builder{}
+		stmt.AddClause(mergeClause)
+		stmt.Build("MERGE")
+	}
+
+	return tx.callbacks.Raw().Execute(tx)
+}
+
+func mergeColumnName(stmt *Statement, name string) string {
+	if stmt.Schema != nil {
+		if field := stmt.Schema.LookUpField(name); field != nil {
+			return field.DBName
+		}
+	}
+	return name
+}
+
+func mergeAssignments(tx *DB, values interface{}, sourceName string) (set clause.Set) {
+	switch v := values.(type) {
+	case clause.Assigner:
+		return v.Assignments()
+	case map[string]interface{}:
+		set = clause.Assignments(v)
+		for idx, assignment := range set {
+			set[idx].Column.Name = mergeColumnName(tx.Statement, assignment.Column.Name)
+		}
+	case []string:
+		for _, column := range v {
+			name := mergeColumnName(tx.Statement, column)
+			set = append(set, clause.Assignment{Column: clause.Column{Name: name}, Value: clause.Column{Table: sourceName, Name: name}})
+		}
+	default:
+		tx.AddError(fmt.Errorf("%w: unsupported merge update values %T", ErrInvalidData, values))
+	}
+	return set
+}
+
+func mergeValues(tx *DB, values interface{}, sourceName string) (result clause.Values) {
+	switch v := values.(type) {
+	case clause.Values:
+		return v
+	case map[string]interface{}:
+		row := make([]interface{}, 0, len(v))
+		for _, assignment := range clause.Assignments(v) {
+			result.Columns = append(result.Columns, clause.Column{Name: mergeColumnName(tx.Statement, assignment.Column.Name)})
+			row = append(row, assignment.Value)
+		}
+		result.Values = [][]interface{}{row}
+	case []string:
+		row := make([]interface{}, 0, len(v))
+		for _, column := range v {
+			name := mergeColumnName(tx.Statement, column)
+			result.Columns = append(result.Columns, clause.Column{Name: name})
+			row = append(row, clause.Column{Table: sourceName, Name: name})
+		}
+		result.Values = [][]interface{}{row}
+	default:
+		tx.AddError(fmt.Errorf("%w: unsupported merge insert values %T", ErrInvalidData, values))
+	}
+	return result
+}
@@ -0,0 +1,271 @@
+package gorm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Page cursors of a keyset page, Next and Prev are opaque cursors of the next and previous pages, they are empty if
+// there is no next or previous page
+type Page struct {
+	Next string
+	Prev string
+}
+
+type pageCursor struct {
+	Prev    bool              `json:"p,omitempty"`
+	Columns []string          `json:"c"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+type keysetColumn struct {
+	column clause.Column
+	field  *schema.Field
+	desc   bool
+}
+
+// Paginate finds a page of at most pageSize records after or before cursor into dest with keyset pagination, records
+// are ordered by the current order columns and the primary key, the first page is found if cursor is empty
+//
+// Order columns should be fields of dest and not nullable, the seek condition is written as row values if all
+// columns are ordered in the same direction, otherwise it is expanded to OR conditions
+//
+//	page, err := db.Order("age desc").Paginate(&users, "", 20)
+//	// SELECT * FROM `users` ORDER BY `users`.`age` DESC,`users`.`id` LIMIT 21
+//	page, err = db.Order("age desc").Paginate(&users, page.Next, 20)
+//	// SELECT * FROM `users` WHERE (`users`.`age` < 18 OR (`users`.`age` = 18 AND `users`.`id` > 20)) ORDER BY `users`.`age` DESC,`users`.`id` LIMIT 21
+func (db *DB) Paginate(dest interface{}, cursor string, pageSize int) (page Page, err error) {
+	if pageSize <= 0 {
+		return page, fmt.Errorf("%w: page size should be greater than 0", ErrInvalidData)
+	}
+
+	reflectValue := reflect.ValueOf(dest)
+	if reflectValue.Kind() != reflect.Ptr || reflectValue.Elem().Kind() != reflect.Slice {
+		return page, fmt.Errorf("%w: paginate dest should be a pointer to slice", ErrInvalidValue)
+	}
+	reflectValue = reflectValue.Elem()
+
+	destSchema, err := schema.Parse(dest, db.cacheStore, db.NamingStrategy)
+	if err != nil {
+		return page, err
+	}
+
+	columns, err := keysetColumns(db.Statement, destSchema)
+	if err != nil {
+		return page, err
+	}
+
+	var (
+		current pageCursor
+		values  []interface{}
+	)
+	if cursor != "" {
+		if current, values, err = decodePageCursor(cursor, columns); err != nil {
+			return page, err
+		}
+	}
+
+	orderBy := clause.OrderBy{Columns: make([]clause.OrderByColumn, len(columns))}
+	for idx, column := range columns {
+		orderBy.Columns[idx] = clause.OrderByColumn{Column: column.column, Desc: column.desc != current.Prev, Reorder: idx == 0}
+	}
+
+	tx := db.Clauses(orderBy)
+	if cursor != "" {
+		tx = tx.Where(keysetCondition(columns, values, current.Prev))
+	}
+
+	if err = tx.Limit(pageSize + 1).Find(dest).Error; err != nil {
+		return page, err
+	}
+
+	hasMore := reflectValue.Len() > pageSize
+	if hasMore {
+		reflectValue.SetLen(pageSize)
+	}
+
+	if current.Prev {
+		swap := reflect.Swapper(reflectValue.Interface())
+		for i, j := 0, reflectValue.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	if length := reflectValue.Len(); length > 0 {
+		if hasMore || current.Prev {
+			if page.Next, err = encodePageCursor(db, columns, reflectValue.Index(length-1), false); err != nil {
+				return page, err
+			}
+		}
+
+		if (hasMore && current.Prev) || (cursor != "" && !current.Prev) {
+			if page.Prev, err = encodePageCursor(db, columns, reflectValue.Index(0), true); err != nil {
+				return page, err
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// keysetColumns returns order columns of stmt, the primary key is appended if it is not ordered
+func keysetColumns(stmt *Statement, destSchema *schema.Schema) (columns []keysetColumn, err error) {
+	appendColumn := func(table, name string, desc bool) error {
+		field := destSchema.LookUpField(name)
+		if field == nil {
+			return fmt.Errorf("%w: order column %s is not a field of %s, keyset pagination requires it", ErrInvalidField, name, destSchema.Name)
+		}
+
+		if table == "" {
+			table = clause.CurrentTable
+		}
+		columns = append(columns, keysetColumn{column: clause.Column{Table: table, Name: field.DBName}, field: field, desc: desc})
+		return nil
+	}
+
+	if c, ok := stmt.Clauses["ORDER BY"]; ok {
+		orderBy, ok := c.Expression.(clause.OrderBy)
+		if !ok || orderBy.Expression != nil {
+			return nil, fmt.Errorf("%w: keyset pagination requires ordering by columns", ErrInvalidData)
+		}
+
+		for _, orderByColumn := range orderBy.Columns {
+			if !orderByColumn.Column.Raw {
+				if err = appendColumn(orderByColumn.Column.Table, orderByColumn.Column.Name, orderByColumn.Desc); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			// raw orders, e.g. "age desc, users.id"
+			for _, order := range strings.Split(orderByColumn.Column.Name, ",") {
+				fields := strings.Fields(order)
+				if len(fields) == 0 || len(fields) > 2 || (len(fields) == 2 && !strings.EqualFold(fields[1], "asc") && !strings.EqualFold(fields[1], "desc")) {
+					return nil, fmt.Errorf("%w: keyset pagination requires ordering by columns, got %s", ErrInvalidData, order)
+				}
+
+				var table string
+				name := strings.Trim(fields[0], "`\"[]")
+				if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+					table, name = strings.Trim(name[:idx], "`\"[]"), strings.Trim(name[idx+1:], "`\"[]")
+				}
+
+				if err = appendColumn(table, name, len(fields) == 2 && strings.EqualFold(fields[1], "desc")); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	for _, field := range destSchema.PrimaryFields {
+		ordered := false
+		for _, column := range columns {
+			if column.field.DBName == field.DBName {
+				ordered = true
+				break
+			}
+		}
+
+		if !ordered {
+			columns = append(columns, keysetColumn{column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, field: field})
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: keyset pagination requires order columns or primary keys", ErrInvalidData)
+	}
+	return columns, nil
+}
+
+// keysetCondition returns the seek condition of rows after values, or before values if prev is true
+func keysetCondition(columns []keysetColumn, values []interface{}, prev bool) clause.Expression {
+	sameDirection := true
+	for _, column := range columns {
+		sameDirection = sameDirection && column.desc == columns[0].desc
+	}
+
+	operator := func(column keysetColumn) string {
+		if column.desc != prev {
+			return "<"
+		}
+		return ">"
+	}
+
+	if sameDirection {
+		if len(columns) == 1 {
+			return clause.Expr{SQL: "? " + operator(columns[0]) + " ?", Vars: []interface{}{columns[0].column, values[0]}}
+		}
+
+		cols := make([]clause.Column, len(columns))
+		for idx, column := range columns {
+			cols[idx] = column.column
+		}
+		return clause.Expr{SQL: "? " + operator(columns[0]) + " ?", Vars: []interface{}{cols, values}}
+	}
+
+	exprs := make([]clause.Expression, len(columns))
+	for idx, column := range columns {
+		conds := make([]clause.Expression, 0, idx+1)
+		for i := 0; i < idx; i++ {
+			conds = append(conds, clause.Eq{Column: columns[i].column, Value: values[i]})
+		}
+		conds = append(conds, clause.Expr{SQL: "? " + operator(column) + " ?", Vars: []interface{}{column.column, values[idx]}})
+		exprs[idx] = clause.And(conds...)
+	}
+	return clause.Or(exprs...)
+}
+
+func encodePageCursor(db *DB, columns []keysetColumn, row reflect.Value, prev bool) (string, error) {
+	cursor := pageCursor{Prev: prev, Columns: make([]string, len(columns)), Values: make([]json.RawMessage, len(columns))}
+	for idx, column := range columns {
+		value, _ := column.field.ValueOf(db.Statement.Context, reflect.Indirect(row))
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		cursor.Columns[idx] = column.field.DBName
+		cursor.Values[idx] = data
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePageCursor(token string, columns []keysetColumn) (cursor pageCursor, values []interface{}, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+
+	if err != nil {
+		return cursor, nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	if len(cursor.Columns) != len(columns) || len(cursor.Values) != len(columns) {
+		return cursor, nil, fmt.Errorf("%w: cursor columns %v don't match the order", ErrInvalidCursor, cursor.Columns)
+	}
+
+	values = make([]interface{}, len(columns))
+	for idx, column := range columns {
+		if cursor.Columns[idx] != column.field.DBName {
+			return cursor, nil, fmt.Errorf("%w: cursor columns %v don't match the order", ErrInvalidCursor, cursor.Columns)
+		}
+
+		value := reflect.New(column.field.FieldType)
+		if err = json.Unmarshal(cursor.Values[idx], value.Interface()); err != nil {
+			return cursor, nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		values[idx] = value.Elem().Interface()
+	}
+	return cursor, values, nil
+}
@@ -0,0 +1,438 @@
+// Package audit provides a plugin auditing changes made by GORM statements.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// Audit change audit plugin, rows created, updated and deleted by statements of models are written to Sink as logs
+// with changed columns, including bulk updates and updates of UpdateColumn
+//
+// Old values are queried with the conditions and joins of updates and deletes if PreImage is enabled, updated rows
+// are queried again after updates to get their new values. Otherwise old values are taken from models with
+// Statement.Changed, e.g. db.Model(&user).Updates(values), they are unknown for bulk updates and updates of models
+// themselves like Save
+//
+//	db.Use(&audit.Audit{PreImage: true})
+//	db.WithContext(audit.WithActor(ctx, "admin")).Model(&User{}).Where("role = ?", "guest").Update("active", false)
+type Audit struct {
+	// Sink writes audit logs, TableSink is used by default
+	Sink Sink
+	// Tables audited tables, all tables are audited if it is empty
+	Tables []string
+	// PreImage query old values of rows before updates and deletes
+	PreImage bool
+	// Actor returns the actor of changes, ActorFromContext is used by default
+	Actor func(ctx context.Context) string
+}
+
+const imageKey = "gorm:audit:image"
+
+// image values of rows before the statement is executed
+type image struct {
+	rows    []map[string]interface{}
+	model   map[string]interface{}
+	changed map[string]bool
+}
+
+// Name plugin name
+func (a *Audit) Name() string {
+	return "gorm:audit"
+}
+
+// Initialize register callbacks auditing statements
+func (a *Audit) Initialize(db *gorm.DB) error {
+	if a.Sink == nil {
+		a.Sink = TableSink{}
+	}
+
+	if a.Actor == nil {
+		a.Actor = ActorFromContext
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:after_create").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit", a.afterCreate),
+		callbacks.Update().Before("gorm:update").Register("gorm:audit:before_update", a.before),
+		callbacks.Update().After("gorm:after_update").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit", a.afterUpdate),
+		callbacks.Delete().Before("gorm:delete").Register("gorm:audit:before_delete", a.before),
+		callbacks.Delete().After("gorm:after_delete").Before("gorm:commit_or_rollback_transaction").Register("gorm:audit", a.afterDelete),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// audited returns true if changes of the statement are audited, logs written by TableSink are not audited
+func (a *Audit) audited(db *gorm.DB) bool {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return false
+	}
+
+	// matches both TableSink and *TableSink
+	if sink, ok := a.Sink.(interface{ tableName() string }); ok && db.Statement.Table == sink.tableName() {
+		return false
+	}
+	return len(a.Tables) == 0 || utils.Contains(a.Tables, db.Statement.Table)
+}
+
+// before saves images of rows before updates and deletes
+func (a *Audit) before(db *gorm.DB) {
+	if !a.audited(db) {
+		return
+	}
+
+	var (
+		stmt  = db.Statement
+		img   image
+		model = reflect.Indirect(stmt.ReflectValue)
+	)
+
+	// values of models without primary keys aren't values of rows
+	if model.Kind() == reflect.Struct {
+		if img.model = rowValues(stmt, model); primaryKey(stmt, img.model) == "" {
+			img.model = nil
+		} else if stmt.Dest != stmt.Model {
+			img.changed = map[string]bool{}
+			for _, field := range stmt.Schema.Fields {
+				if field.DBName != "" {
+					img.changed[field.DBName] = stmt.Changed(field.Name)
+				}
+			}
+		}
+	}
+
+	if a.PreImage {
+		var conds []clause.Expression
+		if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+			conds = append(conds, where.Exprs...)
+		}
+
+		if _, values := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields); len(values) > 0 {
+			column, queryValues := schema.ToQueryValues(clause.CurrentTable, stmt.Schema.PrimaryFieldDBNames, values)
+			conds = append(conds, clause.IN{Column: column, Values: queryValues})
+		}
+
+		// statements without conditions are rejected unless global updates are allowed
+		if len(conds) > 0 || db.AllowGlobalUpdate {
+			rows, err := queryRows(db, conds, stmt.Unscoped, true)
+			if db.AddError(err) != nil {
+				return
+			}
+			img.rows = rows
+		}
+	}
+
+	stmt.Settings.Store(imageKey, img)
+}
+
+func (a *Audit) afterCreate(db *gorm.DB) {
+	if !a.audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	var logs []Log
+	appendLog := func(values map[string]interface{}) {
+		log := a.newLog(db, ActionCreate, primaryKey(db.Statement, values))
+		for _, column := range sortedColumns(values) {
+			log.Changes = append(log.Changes, Change{Column: column, New: values[column]})
+		}
+		logs = append(logs, log)
+	}
+
+	switch value := reflect.Indirect(db.Statement.ReflectValue); value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			appendLog(rowValues(db.Statement, reflect.Indirect(value.Index(i))))
+		}
+	default:
+		appendLog(rowValues(db.Statement, value))
+	}
+
+	a.write(db, logs)
+}
+
+func (a *Audit) afterUpdate(db *gorm.DB) {
+	img, ok := loadImage(db)
+	if !ok || !a.audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	var logs []Log
+	if a.PreImage {
+		rows, err := queryRows(db, primaryKeyConds(db.Statement, img.rows), true, false)
+		if db.AddError(err) != nil {
+			return
+		}
+
+		newRows := make(map[string]map[string]interface{}, len(rows))
+		for _, row := range rows {
+			newRows[primaryKey(db.Statement, row)] = row
+		}
+
+		for _, row := range img.rows {
+			key := primaryKey(db.Statement, row)
+			if newRow, ok := newRows[key]; ok {
+				log := a.newLog(db, ActionUpdate, key)
+				for _, column := range sortedColumns(newRow) {
+					if !utils.AssertEqual(row[column], newRow[column]) {
+						log.Changes = append(log.Changes, Change{Column: column, Old: row[column], New: newRow[column]})
+					}
+				}
+
+				if len(log.Changes) > 0 {
+					logs = append(logs, log)
+				}
+			}
+		}
+	} else {
+		var (
+			values = updatedValues(db.Statement)
+			log    = a.newLog(db, ActionUpdate, primaryKey(db.Statement, img.model))
+		)
+
+		for _, column := range sortedColumns(values) {
+			change := Change{Column: column, New: values[column]}
+			if old, ok := img.model[column]; ok && img.changed != nil {
+				// values of the model are old values if they are not the updating values
+				if changed, ok := img.changed[column]; ok && !changed && utils.AssertEqual(old, change.New) {
+					continue
+				}
+				change.Old = old
+			}
+			log.Changes = append(log.Changes, change)
+		}
+
+		if len(log.Changes) > 0 {
+			logs = append(logs, log)
+		}
+	}
+
+	a.write(db, logs)
+}
+
+func (a *Audit) afterDelete(db *gorm.DB) {
+	img, ok := loadImage(db)
+	if !ok || !a.audited(db) || db.RowsAffected == 0 {
+		return
+	}
+
+	rows := img.rows
+	if !a.PreImage {
+		rows = []map[string]interface{}{img.model}
+	}
+
+	logs := make([]Log, 0, len(rows))
+	for _, row := range rows {
+		log := a.newLog(db, ActionDelete, primaryKey(db.Statement, row))
+		for _, column := range sortedColumns(row) {
+			log.Changes = append(log.Changes, Change{Column: column, Old: row[column]})
+		}
+		logs = append(logs, log)
+	}
+
+	a.write(db, logs)
+}
+
+func (a *Audit) newLog(db *gorm.DB, action, primaryKey string) Log {
+	return Log{
+		Table:      db.Statement.Table,
+		PrimaryKey: primaryKey,
+		Action:     action,
+		Actor:      a.Actor(db.Statement.Context),
+		CreatedAt:  db.NowFunc(),
+	}
+}
+
+func (a *Audit) write(db *gorm.DB, logs []Log) {
+	if len(logs) > 0 && !db.DryRun {
+		if err := a.Sink.Write(db, logs); err != nil {
+			db.AddError(fmt.Errorf("failed to write audit logs: %w", err))
+		}
+	}
+}
+
+func loadImage(db *gorm.DB) (image, bool) {
+	v, ok := db.Statement.Settings.LoadAndDelete(imageKey)
+	if !ok {
+		return image{}, false
+	}
+	img, ok := v.(image)
+	return img, ok
+}
+
+// queryRows queries rows of the statement table matching conds in the same connection, rows are filtered by the joins
+// of the statement too if joined, e.g. joined updates and deletes
+func queryRows(db *gorm.DB, conds []clause.Expression, unscoped, joined bool) (rows []map[string]interface{}, err error) {
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(db.Statement.Schema.ModelType).Interface()).Table(db.Statement.Table)
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+
+	if joined {
+		// columns of joined tables aren't values of rows
+		for _, join := range db.Statement.Joins {
+			join.Omits = []string{"*"}
+			tx.Statement.Joins = append(tx.Statement.Joins, join)
+		}
+	}
+
+	if len(conds) > 0 {
+		tx = tx.Clauses(clause.Where{Exprs: conds})
+	}
+	if err = tx.Find(&rows).Error; err != nil || len(tx.Statement.Joins) == 0 {
+		return rows, err
+	}
+
+	// rows are repeated if has many relations are joined
+	var (
+		keys       = make(map[string]bool, len(rows))
+		uniqueRows = rows[:0]
+	)
+	for _, row := range rows {
+		if key := primaryKey(db.Statement, row); key == "" || !keys[key] {
+			keys[key] = true
+			uniqueRows = append(uniqueRows, row)
+		}
+	}
+	return uniqueRows, nil
+}
+
+func primaryKeyConds(stmt *gorm.Statement, rows []map[string]interface{}) []clause.Expression {
+	values := make([][]interface{}, 0, len(rows))
+	for _, row := range rows {
+		value := make([]interface{}, len(stmt.Schema.PrimaryFieldDBNames))
+		for idx, dbName := range stmt.Schema.PrimaryFieldDBNames {
+			value[idx] = row[dbName]
+		}
+		values = append(values, value)
+	}
+
+	if len(values) == 0 || len(stmt.Schema.PrimaryFieldDBNames) == 0 {
+		return []clause.Expression{clause.Expr{SQL: "1 = 0"}}
+	}
+
+	column, queryValues := schema.ToQueryValues(clause.CurrentTable, stmt.Schema.PrimaryFieldDBNames, values)
+	return []clause.Expression{clause.IN{Column: column, Values: queryValues}}
+}
+
+// primaryKey returns primary key values of row joined by commas, it is empty if they are zero
+func primaryKey(stmt *gorm.Statement, row map[string]interface{}) string {
+	values := make([]string, 0, len(stmt.Schema.PrimaryFieldDBNames))
+	for _, dbName := range stmt.Schema.PrimaryFieldDBNames {
+		value := row[dbName]
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return ""
+		}
+		values = append(values, fmt.Sprint(value))
+	}
+	return strings.Join(values, ",")
+}
+
+// rowValues returns column values of a model value or map
+func rowValues(stmt *gorm.Statement, value reflect.Value) map[string]interface{} {
+	switch value.Kind() {
+	case reflect.Struct:
+		values := make(map[string]interface{}, len(stmt.Schema.DBNames))
+		for _, dbName := range stmt.Schema.DBNames {
+			if field := stmt.Schema.FieldsByDBName[dbName]; field.Readable {
+				values[dbName], _ = field.ValueOf(stmt.Context, value)
+			}
+		}
+		return values
+	case reflect.Map:
+		if values, ok := value.Interface().(map[string]interface{}); ok {
+			return columnValues(stmt, values)
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// columnValues returns values keyed by column names, keys could be field names or column names
+func columnValues(stmt *gorm.Statement, values map[string]interface{}) map[string]interface{} {
+	results := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if field := stmt.Schema.LookUpField(key); field != nil && field.DBName != "" {
+			key = field.DBName
+		}
+		results[key] = value
+	}
+	return results
+}
+
+// updatedValues returns columns and their values updated by the statement, updating values of structs are values
+// of selected or non-zero fields
+func updatedValues(stmt *gorm.Statement) map[string]interface{} {
+	var (
+		values                    = map[string]interface{}{}
+		model                     = reflect.Indirect(stmt.ReflectValue)
+		selectColumns, restricted = stmt.SelectAndOmitColumns(false, true)
+	)
+
+	updatingValue := reflect.ValueOf(stmt.Dest)
+	for updatingValue.Kind() == reflect.Ptr {
+		updatingValue = updatingValue.Elem()
+	}
+
+	switch dest := updatingValue.Interface().(type) {
+	case map[string]interface{}:
+		for column, value := range columnValues(stmt, dest) {
+			if v, ok := selectColumns[column]; (ok && v) || (!ok && !restricted) {
+				values[column] = value
+			}
+		}
+
+		// auto update time fields are assigned to the model
+		if model.Kind() == reflect.Struct && !stmt.SkipHooks {
+			for _, field := range stmt.Schema.Fields {
+				if _, ok := values[field.DBName]; !ok && field.AutoUpdateTime > 0 {
+					if v, ok := selectColumns[field.DBName]; (ok && v) || !ok {
+						values[field.DBName], _ = field.ValueOf(stmt.Context, model)
+					}
+				}
+			}
+		}
+	default:
+		if updatingValue.Kind() != reflect.Struct {
+			return values
+		}
+
+		updatingStmt := &gorm.Statement{DB: stmt.DB}
+		if err := updatingStmt.Parse(stmt.Dest); err != nil {
+			return values
+		}
+
+		for _, field := range updatingStmt.Schema.Fields {
+			if field.DBName == "" || !field.Updatable || (field.PrimaryKey && stmt.Dest == stmt.Model) {
+				continue
+			}
+
+			value, isZero := field.ValueOf(stmt.Context, updatingValue)
+			if v, ok := selectColumns[field.DBName]; (ok && v) || (!ok && !restricted && !isZero) {
+				values[field.DBName] = value
+			}
+		}
+	}
+	return values
+}
+
+func sortedColumns(values map[string]interface{}) []string {
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Actions of audit logs
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// Log audit log of a changed row, PrimaryKey is empty if the row is unknown, e.g. rows of bulk updates without
+// pre-images
+type Log struct {
+	ID         uint64   `gorm:"primaryKey"`
+	Table      string   `gorm:"size:128;index"`
+	PrimaryKey string   `gorm:"size:256"`
+	Action     string   `gorm:"size:16"`
+	Changes    []Change `gorm:"serializer:json"`
+	Actor      string   `gorm:"size:256"`
+	CreatedAt  time.Time
+}
+
+// Change change of a column, Old is nil if the old value is unknown
+type Change struct {
+	Column string      `json:"column"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+}
+
+// Sink writes audit logs, db is the db of the audited statement, logs written with it are in the same transaction
+type Sink interface {
+	Write(db *gorm.DB, logs []Log) error
+}
+
+// TableSink writes audit logs to Table, "audit_logs" by default, the table could be created with
+//
+//	db.Table("audit_logs").AutoMigrate(&audit.Log{})
+type TableSink struct {
+	Table string
+}
+
+func (sink TableSink) tableName() string {
+	if sink.Table == "" {
+		return "audit_logs"
+	}
+	return sink.Table
+}
+
+// Write creates logs in the table
+func (sink TableSink) Write(db *gorm.DB, logs []Log) error {
+	return db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(sink.tableName()).Create(&logs).Error
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose changes are audited as made by actor
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor of ctx
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
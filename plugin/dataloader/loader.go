@@ -0,0 +1,187 @@
+// Package dataloader provides batched lazy loading of relationships, loads of a relationship of many records
+// requested within a window are batched into a single preload query.
+package dataloader
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// Loader lazy relationship loader, loads of the same relationship requested within Wait are batched and preloaded
+// with a single IN query, a loader is usually created per request, batches run with the context of DB rather than
+// of any caller, so a caller canceling its load doesn't fail loads of others
+//
+//	loader := dataloader.New(db)
+//	// resolvers of users run concurrently
+//	func (r *userResolver) Pets(ctx context.Context, user *User) ([]*Pet, error) {
+//	  err := loader.Lazy(user, "Pets").Load(ctx)
+//	  return user.Pets, err
+//	}
+type Loader struct {
+	// DB db loading relationships
+	DB *gorm.DB
+	// Wait window batching loads, 1ms by default
+	Wait time.Duration
+	// MaxBatch max records of a batch, batches are loaded once they are full, unlimited if zero
+	MaxBatch int
+
+	mu      sync.Mutex
+	batches map[batchKey]*batch
+}
+
+type batchKey struct {
+	modelType reflect.Type
+	name      string
+}
+
+// batch records loading a relationship
+type batch struct {
+	conds  []interface{}
+	values reflect.Value
+	seen   map[interface{}]int
+	done   chan struct{}
+	err    error
+}
+
+// New returns a loader of db
+func New(db *gorm.DB) *Loader {
+	return &Loader{DB: db}
+}
+
+// Lazy returns the lazy relationship name of value, a pointer to a struct, nested relationships like "Pets.Toy" are
+// supported, conds are conditions of Preload, loads batched together use conditions of the first one
+func (l *Loader) Lazy(value interface{}, name string, conds ...interface{}) *Relation {
+	return &Relation{loader: l, value: value, name: name, conds: conds}
+}
+
+// Relation lazy relationship of a record
+type Relation struct {
+	loader *Loader
+	value  interface{}
+	name   string
+	conds  []interface{}
+
+	mu     sync.Mutex
+	loaded bool
+}
+
+// Load loads the relationship to the record with loads of other records in the same batch, it returns immediately
+// if the relationship is loaded, if ctx is done before the batch is dispatched the record is removed from the batch,
+// otherwise the record is still written by the running batch and shouldn't be read until the batch finishes
+func (r *Relation) Load(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.loaded {
+		return nil
+	}
+
+	value := reflect.ValueOf(r.value)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return gorm.ErrInvalidValue
+	}
+
+	err := r.loader.load(ctx, value, r.name, r.conds)
+	r.loaded = err == nil
+	return err
+}
+
+func (l *Loader) load(ctx context.Context, value reflect.Value, name string, conds []interface{}) error {
+	key := batchKey{modelType: value.Type(), name: name}
+
+	l.mu.Lock()
+	if l.batches == nil {
+		l.batches = map[batchKey]*batch{}
+	}
+
+	b, ok := l.batches[key]
+	if !ok {
+		b = &batch{
+			conds:  conds,
+			values: reflect.MakeSlice(reflect.SliceOf(value.Type()), 0, 10),
+			seen:   map[interface{}]int{},
+			done:   make(chan struct{}),
+		}
+		l.batches[key] = b
+
+		wait := l.Wait
+		if wait <= 0 {
+			wait = time.Millisecond
+		}
+		time.AfterFunc(wait, func() { l.dispatch(key, b) })
+	}
+
+	// records are loaded once in a batch
+	if b.seen[value.Interface()] == 0 {
+		b.values = reflect.Append(b.values, value)
+	}
+	b.seen[value.Interface()]++
+	full := l.MaxBatch > 0 && b.values.Len() >= l.MaxBatch
+	l.mu.Unlock()
+
+	if full {
+		l.dispatch(key, b)
+	}
+
+	select {
+	case <-b.done:
+		return b.err
+	case <-ctx.Done():
+		l.cancel(key, b, value)
+		return ctx.Err()
+	}
+}
+
+// cancel removes the record from the batch if the batch is not dispatched and no other load waits for it
+func (l *Loader) cancel(key batchKey, b *batch, value reflect.Value) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.batches[key] != b {
+		return
+	}
+
+	if b.seen[value.Interface()]--; b.seen[value.Interface()] > 0 {
+		return
+	}
+	delete(b.seen, value.Interface())
+
+	values := reflect.MakeSlice(b.values.Type(), 0, b.values.Len())
+	for i := 0; i < b.values.Len(); i++ {
+		if b.values.Index(i).Interface() != value.Interface() {
+			values = reflect.Append(values, b.values.Index(i))
+		}
+	}
+	b.values = values
+}
+
+// dispatch preloads the relationship of records of the batch if it is not dispatched
+func (l *Loader) dispatch(key batchKey, b *batch) {
+	l.mu.Lock()
+	if l.batches[key] != b {
+		l.mu.Unlock()
+		return
+	}
+	delete(l.batches, key)
+	l.mu.Unlock()
+
+	if b.values.Len() == 0 {
+		close(b.done)
+		return
+	}
+
+	tx := l.DB.Session(&gorm.Session{NewDB: true}).Preload(key.name, b.conds...)
+	if tx.AddError(tx.Statement.Parse(b.values.Interface())) == nil {
+		tx.Statement.Dest = b.values.Interface()
+		tx.Statement.ReflectValue = b.values
+		callbacks.Preload(tx)
+	}
+
+	b.err = tx.Error
+	close(b.done)
+}
@@ -0,0 +1,266 @@
+// Package encryption provides transparent field-level encryption with key rotation and blind indexes.
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Encryption field-level encryption plugin, it registers Serializer as `serializer:encrypted` and maintains blind
+// indexes, fields tagged with `blindindex:<field>` are set to HMACs of the plaintext of the field when it's written,
+// they are looked up with BlindIndex
+//
+//	type User struct {
+//	  ID         uint
+//	  Email      string `gorm:"serializer:encrypted"`
+//	  EmailIndex string `gorm:"blindindex:Email;index"`
+//	}
+//
+//	db.Use(&encryption.Encryption{Keyring: keyring, IndexKey: indexKey})
+//	db.Where("email_index = ?", enc.BlindIndex("jinzhu@example.org")).First(&user)
+//
+// Serializers are registered globally, databases of a process using the plugin share the keyring, using it with
+// another keyring returns an error
+type Encryption struct {
+	// Keyring keys encrypting values
+	Keyring Keyring
+	// IndexKey HMAC key of blind indexes, it's not rotated with the keyring as blind indexes are looked up with it,
+	// it's required by models with blind indexes
+	IndexKey []byte
+}
+
+// SerializerName name of the registered serializer
+const SerializerName = "encrypted"
+
+// Name plugin name
+func (e *Encryption) Name() string {
+	return "gorm:encryption"
+}
+
+// Initialize register the serializer and callbacks maintaining blind indexes
+func (e *Encryption) Initialize(db *gorm.DB) error {
+	if e.Keyring == nil {
+		return errors.New("encryption keyring required")
+	}
+
+	if registered, ok := schema.GetSerializer(SerializerName); ok {
+		if serializer, ok := registered.(Serializer); !ok || !sameKeyring(serializer.Keyring, e.Keyring) {
+			return fmt.Errorf("serializer %s is registered with another keyring", SerializerName)
+		}
+	}
+	schema.RegisterSerializer(SerializerName, Serializer{Keyring: e.Keyring})
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("gorm:encryption", e.beforeSave),
+		callbacks.Update().Before("gorm:update").Register("gorm:encryption", e.beforeSave),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sameKeyring returns true if keyrings are the same, uncomparable keyrings are compared by their values
+func sameKeyring(keyring, other Keyring) bool {
+	if reflect.TypeOf(keyring) != reflect.TypeOf(other) {
+		return false
+	}
+
+	if reflect.TypeOf(keyring).Comparable() {
+		return keyring == other
+	}
+	return reflect.DeepEqual(keyring, other)
+}
+
+// BlindIndex returns the blind index of value, strings and bytes are indexed as they are, other values as JSON
+func (e *Encryption) BlindIndex(value interface{}) string {
+	plaintext, ok, err := encode(value)
+	if err != nil || !ok {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, e.IndexKey)
+	mac.Write(plaintext)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// beforeSave sets blind indexes of values written by the statement and encrypts values of maps, which aren't
+// serialized by gorm
+func (e *Encryption) beforeSave(db *gorm.DB) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil {
+		return
+	}
+
+	var maps []map[string]interface{}
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		maps = append(maps, dest)
+	case *map[string]interface{}:
+		maps = append(maps, *dest)
+	case []map[string]interface{}:
+		maps = dest
+	case *[]map[string]interface{}:
+		maps = *dest
+	default:
+		if stmt.ReflectValue.CanAddr() && stmt.Dest != stmt.Model && stmt.Model != nil {
+			// updates of structs other than the model, copies of them are updated if they aren't addressable
+			if reflectValue := reflect.ValueOf(stmt.Dest); reflectValue.Kind() == reflect.Struct {
+				copied := reflect.New(reflectValue.Type())
+				copied.Elem().Set(reflectValue)
+				stmt.Dest = copied.Interface()
+			}
+		}
+	}
+
+	for _, field := range stmt.Schema.Fields {
+		name, ok := field.TagSettings["BLINDINDEX"]
+		if !ok {
+			continue
+		}
+
+		source := stmt.Schema.LookUpField(name)
+		if source == nil {
+			db.AddError(fmt.Errorf("invalid blind index field %s of field %s", name, field.Name))
+			return
+		}
+
+		if len(e.IndexKey) == 0 {
+			db.AddError(fmt.Errorf("index key required by blind index field %s", field.Name))
+			return
+		}
+
+		// blind indexes of selected fields are updated with them
+		if len(stmt.Selects) > 0 {
+			if selectColumns, restricted := stmt.SelectAndOmitColumns(false, true); restricted && selectColumns[source.DBName] {
+				stmt.Selects = append(stmt.Selects, field.Name)
+			}
+		}
+
+		for _, values := range maps {
+			for _, key := range []string{source.Name, source.DBName} {
+				if value, ok := values[key]; ok {
+					values[field.DBName] = e.BlindIndex(value)
+					break
+				}
+			}
+		}
+
+		// blind indexes of zero values are zero, they are not updated by updates of structs like their fields
+		setIndex := func(rv reflect.Value) {
+			if rv = reflect.Indirect(rv); rv.Kind() == reflect.Struct && rv.CanAddr() {
+				// values of serializer fields are wrapped by ValueOf
+				if value := source.ReflectValueOf(stmt.Context, rv); value.IsZero() {
+					db.AddError(field.Set(stmt.Context, rv, reflect.Zero(field.FieldType).Interface()))
+				} else {
+					db.AddError(field.Set(stmt.Context, rv, e.BlindIndex(value.Interface())))
+				}
+			}
+		}
+
+		if maps == nil {
+			reflectValue := stmt.ReflectValue
+			if stmt.Dest != stmt.Model {
+				reflectValue = reflect.ValueOf(stmt.Dest)
+			}
+
+			if reflectValue = reflect.Indirect(reflectValue); reflectValue.Kind() == reflect.Slice || reflectValue.Kind() == reflect.Array {
+				for i := 0; i < reflectValue.Len(); i++ {
+					setIndex(reflectValue.Index(i))
+				}
+			} else {
+				setIndex(reflectValue)
+			}
+		}
+	}
+
+	for _, values := range maps {
+		e.encryptValues(db, values)
+	}
+}
+
+// encryptValues replaces values of encrypted fields in values with their serializer valuers
+func (e *Encryption) encryptValues(db *gorm.DB, values map[string]interface{}) {
+	stmt := db.Statement
+	for _, field := range stmt.Schema.Fields {
+		if !encrypted(field) {
+			continue
+		}
+
+		for _, key := range []string{field.Name, field.DBName} {
+			value, ok := values[key]
+			if _, isExpr := value.(clause.Expression); !ok || value == nil || isExpr {
+				continue
+			}
+
+			// values are set to a new model as ValueOf wraps values of fields with their serializers
+			model := reflect.New(stmt.Schema.ModelType)
+			if err := field.Set(stmt.Context, model, value); err != nil {
+				db.AddError(err)
+				return
+			}
+			values[key], _ = field.ValueOf(stmt.Context, model)
+		}
+	}
+}
+
+func encrypted(field *schema.Field) bool {
+	return strings.EqualFold(field.TagSettings["SERIALIZER"], SerializerName) && field.DBName != ""
+}
+
+// Rotate re-encrypts encrypted fields of records of dest, a pointer to a slice of models, which aren't encrypted with
+// the current key, in batches of batchSize found with FindInBatches, it returns the number of rotated records
+//
+//	rotated, err := enc.Rotate(db, &[]User{}, 100)
+func (e *Encryption) Rotate(db *gorm.DB, dest interface{}, batchSize int) (rotated int64, err error) {
+	stmt := &gorm.Statement{DB: db}
+	if err = stmt.Parse(dest); err != nil {
+		return 0, err
+	}
+
+	var (
+		names []string
+		conds []clause.Expression
+		// stored values start with key ids
+		currentPrefix = e.Keyring.Current() + ":%"
+	)
+	for _, field := range stmt.Schema.Fields {
+		if encrypted(field) {
+			names = append(names, field.Name)
+			conds = append(conds, clause.Not(clause.Like{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: currentPrefix}))
+		}
+	}
+
+	if len(names) == 0 {
+		return 0, nil
+	}
+
+	result := db.Where(clause.Or(conds...)).FindInBatches(dest, batchSize, func(tx *gorm.DB, batch int) error {
+		records := reflect.Indirect(reflect.ValueOf(dest))
+		for i := 0; i < records.Len(); i++ {
+			record := records.Index(i)
+			if record.Kind() != reflect.Ptr {
+				record = record.Addr()
+			}
+
+			result := db.Session(&gorm.Session{NewDB: true}).Model(record.Interface()).Select(names).UpdateColumns(record.Interface())
+			if result.Error != nil {
+				return result.Error
+			}
+			rotated += result.RowsAffected
+		}
+		return nil
+	})
+	return rotated, result.Error
+}
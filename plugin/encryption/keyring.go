@@ -0,0 +1,41 @@
+package encryption
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownKey key of the key id is not in the keyring
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Keyring keys encrypting values, values are encrypted with the current key and decrypted with the key of the id
+// they are encrypted with, so that keys can be rotated
+type Keyring interface {
+	// Current returns the id of the key encrypting new values
+	Current() string
+	// Key returns the AES key of id, 16, 24 or 32 bytes
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyring keyring of static keys
+//
+//	keyring := encryption.StaticKeyring{CurrentID: "2024", Keys: map[string][]byte{"2023": oldKey, "2024": newKey}}
+type StaticKeyring struct {
+	// CurrentID id of the current key
+	CurrentID string
+	// Keys keys by their ids
+	Keys map[string][]byte
+}
+
+// Current returns the id of the current key
+func (keyring StaticKeyring) Current() string {
+	return keyring.CurrentID
+}
+
+// Key returns the key of id
+func (keyring StaticKeyring) Key(id string) ([]byte, error) {
+	if key, ok := keyring.Keys[id]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+}
@@ -0,0 +1,207 @@
+package encryption
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCiphertext stored value is not a value encrypted by Serializer
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+// Serializer AES-GCM encryption serializer, values are stored as `<key id>:<base64 of nonce and ciphertext>`, strings
+// and bytes are encrypted as they are, other values are encrypted as JSON
+//
+// Values of fields with the tag `deterministic` are encrypted with nonces derived from them, equal values of them are
+// encrypted to equal ciphertexts with the same key, keys of the keyring are not used directly, subkeys of encryption
+// and nonces are derived from them with HKDF
+//
+//	type User struct {
+//	  ID    uint
+//	  Email string `gorm:"serializer:encrypted"`
+//	  SSN   string `gorm:"serializer:encrypted;deterministic"`
+//	}
+type Serializer struct {
+	Keyring Keyring
+}
+
+// Scan implements serializer interface
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+
+	var ciphertext string
+	switch v := dbValue.(type) {
+	case []byte:
+		ciphertext = string(v)
+	case string:
+		ciphertext = v
+	case nil:
+	default:
+		return fmt.Errorf("%w of field %s: %#v", ErrInvalidCiphertext, field.Name, dbValue)
+	}
+
+	if ciphertext != "" {
+		plaintext, err := s.Decrypt(ciphertext)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt field %s: %w", field.Name, err)
+		}
+
+		if err := decode(plaintext, fieldValue); err != nil {
+			return fmt.Errorf("failed to decode field %s: %w", field.Name, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value implements serializer interface
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok, err := encode(fieldValue)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	_, deterministic := field.TagSettings["DETERMINISTIC"]
+	return s.Encrypt(plaintext, deterministic)
+}
+
+// Encrypt encrypts plaintext with the current key
+func (s Serializer) Encrypt(plaintext []byte, deterministic bool) (string, error) {
+	id := s.Keyring.Current()
+	if id == "" || strings.Contains(id, ":") {
+		return "", fmt.Errorf("invalid encryption key id %q", id)
+	}
+
+	key, err := s.Keyring.Key(id)
+	if err != nil {
+		return "", err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if deterministic {
+		// nonces of deterministic values are derived from them with a MAC key other than the encryption key, SIV-like
+		mac := hmac.New(sha256.New, deriveKey(key, macKeyInfo, sha256.Size))
+		mac.Write(plaintext)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return id + ":" + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// Decrypt decrypts ciphertext with the key it's encrypted with
+func (s Serializer) Decrypt(ciphertext string) ([]byte, error) {
+	id, encoded, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, ErrInvalidCiphertext
+	}
+
+	key, err := s.Keyring.Key(id)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+const (
+	encryptionKeyInfo = "gorm encryption key"
+	macKeyInfo        = "gorm deterministic nonce key"
+)
+
+// newAEAD returns AES-GCM of the encryption subkey of key, keys of AES-128, AES-192 and AES-256 are supported
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if n := len(key); n != 16 && n != 24 && n != 32 {
+		return nil, aes.KeySizeError(n)
+	}
+
+	block, err := aes.NewCipher(deriveKey(key, encryptionKeyInfo, len(key)))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives a subkey of size up to sha256.Size bytes for info from key with HKDF-SHA256 (RFC 5869)
+func deriveKey(key []byte, info string, size int) []byte {
+	// extract with the default salt of zeros
+	extract := hmac.New(sha256.New, make([]byte, sha256.Size))
+	extract.Write(key)
+
+	// expand, a single block is enough for subkeys of sha256.Size bytes
+	expand := hmac.New(sha256.New, extract.Sum(nil))
+	expand.Write([]byte(info))
+	expand.Write([]byte{1})
+	return expand.Sum(nil)[:size]
+}
+
+// encode returns the plaintext of value, ok is false if value is nil
+func encode(value interface{}) (plaintext []byte, ok bool, err error) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch {
+	case !rv.IsValid():
+		return nil, false, nil
+	case rv.Kind() == reflect.String:
+		return []byte(rv.String()), true, nil
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		if rv.IsNil() {
+			return nil, false, nil
+		}
+		return rv.Bytes(), true, nil
+	}
+
+	plaintext, err = json.Marshal(rv.Interface())
+	return plaintext, true, err
+}
+
+// decode decodes plaintext to value
+func decode(plaintext []byte, value reflect.Value) error {
+	for value.Kind() == reflect.Ptr {
+		value.Set(reflect.New(value.Type().Elem()))
+		value = value.Elem()
+	}
+
+	switch {
+	case value.Kind() == reflect.String:
+		value.SetString(string(plaintext))
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		value.SetBytes(append([]byte(nil), plaintext...))
+	default:
+		return json.Unmarshal(plaintext, value.Addr().Interface())
+	}
+	return nil
+}
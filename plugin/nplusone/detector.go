@@ -0,0 +1,283 @@
+// Package nplusone provides a plugin detecting N+1 queries, for development and tests.
+package nplusone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+	"gorm.io/gorm/utils"
+)
+
+// ErrNPlusOneQuery N+1 query detected
+var ErrNPlusOneQuery = errors.New("N+1 query detected")
+
+// Detector N+1 query detector, queries executed with tracked contexts are fingerprinted by normalized SQL, a query
+// shape executed Threshold times with different parameters from the same caller is reported with the Preload or Joins
+// fixing it if known
+//
+//	db.Use(&nplusone.Detector{Strict: true})
+//	ctx := nplusone.Track(r.Context())
+//	db.WithContext(ctx).Find(&users)
+//	for _, user := range users {
+//	  db.WithContext(ctx).Where("user_id = ?", user.ID).Find(&user.Pets) // N+1 query, use Preload("Pets")
+//	}
+type Detector struct {
+	// Threshold executions of a query shape with different parameters reported as a N+1 query, 3 by default
+	Threshold int
+	// Strict adds ErrNPlusOneQuery to the statement reaching Threshold, tests failing with it
+	Strict bool
+	// Report reports N+1 queries, they are logged as warnings by default
+	Report func(ctx context.Context, finding Finding)
+}
+
+// Finding detected N+1 query
+type Finding struct {
+	// SQL normalized SQL of the query
+	SQL string
+	// Caller file and line of the caller executing the query
+	Caller string
+	// Count executions with different parameters
+	Count int
+	// Fix Preload or Joins fixing the query, e.g. `Preload("Pets")` of `users`, empty if unknown
+	Fix string
+}
+
+func (finding Finding) String() string {
+	message := fmt.Sprintf("%s executed %d times at %s", finding.SQL, finding.Count, finding.Caller)
+	if finding.Fix != "" {
+		message += ", use " + finding.Fix
+	}
+	return message
+}
+
+type trackerKey struct{}
+
+// tracker statements executed with a context
+type tracker struct {
+	mu       sync.Mutex
+	shapes   map[string]*shape
+	schemas  []*schema.Schema
+	findings []Finding
+}
+
+type shape struct {
+	params   map[string]bool
+	reported bool
+}
+
+// Track returns a context tracking queries executed with it, e.g. queries of a request or a test
+func Track(ctx context.Context) context.Context {
+	return context.WithValue(ctx, trackerKey{}, &tracker{shapes: map[string]*shape{}})
+}
+
+// Findings returns N+1 queries detected with the tracked ctx
+func Findings(ctx context.Context) []Finding {
+	t, ok := ctx.Value(trackerKey{}).(*tracker)
+	if !ok {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Finding(nil), t.findings...)
+}
+
+// Name plugin name
+func (d *Detector) Name() string {
+	return "gorm:nplusone"
+}
+
+// Initialize register callbacks tracking queries
+func (d *Detector) Initialize(db *gorm.DB) error {
+	if d.Threshold <= 0 {
+		d.Threshold = 3
+	}
+
+	if d.Report == nil {
+		d.Report = func(ctx context.Context, finding Finding) {
+			db.Logger.Warn(ctx, "N+1 query: %v", finding)
+		}
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Query().After("gorm:query").Register("gorm:nplusone", d.track),
+		callbacks.Row().After("gorm:row").Register("gorm:nplusone", d.track),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Detector) track(db *gorm.DB) {
+	t, ok := db.Statement.Context.Value(trackerKey{}).(*tracker)
+	if !ok || db.Error != nil || db.DryRun || db.Statement.SQL.Len() == 0 {
+		return
+	}
+
+	var (
+		stmt   = db.Statement
+		sql    = normalize(stmt.SQL.String())
+		caller = utils.FileWithLineNum()
+		key    = caller + "\x00" + sql
+		params = fmt.Sprint(stmt.Vars)
+	)
+
+	t.mu.Lock()
+	if stmt.Schema != nil && !t.tracked(stmt.Schema) {
+		t.schemas = append(t.schemas, stmt.Schema)
+	}
+
+	s, ok := t.shapes[key]
+	if !ok {
+		s = &shape{params: map[string]bool{}}
+		t.shapes[key] = s
+	}
+	s.params[params] = true
+
+	if s.reported || len(s.params) < d.Threshold {
+		t.mu.Unlock()
+		return
+	}
+
+	s.reported = true
+	finding := Finding{SQL: sql, Caller: caller, Count: len(s.params), Fix: t.fix(stmt)}
+	t.findings = append(t.findings, finding)
+	t.mu.Unlock()
+
+	d.Report(stmt.Context, finding)
+	if d.Strict {
+		db.AddError(fmt.Errorf("%w: %v", ErrNPlusOneQuery, finding))
+	}
+}
+
+func (t *tracker) tracked(s *schema.Schema) bool {
+	for _, tracked := range t.schemas {
+		if tracked == s {
+			return true
+		}
+	}
+	return false
+}
+
+// fix returns the Preload or Joins loading rows of the statement with the tracked queries of their owners, has many
+// relationships are preferred for queries of slices
+func (t *tracker) fix(stmt *gorm.Statement) string {
+	if stmt.Schema == nil {
+		return ""
+	}
+
+	columns := map[string]bool{}
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok {
+		conditionColumns(where.Exprs, columns)
+	}
+
+	// inline conditions of primary keys, e.g. First(&user, 10)
+	if columns[clause.PrimaryKey] && stmt.Schema.PrioritizedPrimaryField != nil {
+		columns[stmt.Schema.PrioritizedPrimaryField.DBName] = true
+	}
+
+	var (
+		fallback  string
+		findSlice = reflect.Indirect(stmt.ReflectValue).Kind() == reflect.Slice
+	)
+	for _, owner := range t.schemas {
+		names := make([]string, 0, len(owner.Relationships.Relations))
+		for name := range owner.Relationships.Relations {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			rel := owner.Relationships.Relations[name]
+			// relations of has one, has many are registered to their field schemas too
+			if rel.Schema != owner || rel.FieldSchema != stmt.Schema || rel.JoinTable != nil {
+				continue
+			}
+
+			for _, ref := range rel.References {
+				var fix string
+				switch {
+				case ref.OwnPrimaryKey && ref.ForeignKey.Schema == stmt.Schema && columns[ref.ForeignKey.DBName]:
+					// has one, has many rows queried by foreign keys
+					fix = fmt.Sprintf("Preload(%q) of `%s`", rel.Name, owner.Table)
+					if rel.Type == schema.HasOne {
+						fix = fmt.Sprintf("Joins(%q) or %s", rel.Name, fix)
+					}
+				case !ref.OwnPrimaryKey && ref.PrimaryKey != nil && ref.PrimaryKey.Schema == stmt.Schema && columns[ref.PrimaryKey.DBName]:
+					// belongs to rows queried by primary keys
+					fix = fmt.Sprintf("Joins(%q) or Preload(%q) of `%s`", rel.Name, rel.Name, owner.Table)
+				default:
+					continue
+				}
+
+				if (rel.Type == schema.HasMany) == findSlice {
+					return fix
+				} else if fallback == "" {
+					fallback = fix
+				}
+			}
+		}
+	}
+
+	return fallback
+}
+
+var conditionColumnRegexp = regexp.MustCompile("[`\"]?(\\w+)[`\"]?\\s*(?:=|(?i:IN)\\b)")
+
+// conditionColumns collects columns of conditions exprs
+func conditionColumns(exprs []clause.Expression, columns map[string]bool) {
+	column := func(c interface{}) {
+		switch c := c.(type) {
+		case clause.Column:
+			columns[c.Name] = true
+		case string:
+			columns[c[strings.LastIndex(c, ".")+1:]] = true
+		}
+	}
+
+	for _, expr := range exprs {
+		switch expr := expr.(type) {
+		case clause.Eq:
+			column(expr.Column)
+		case clause.IN:
+			column(expr.Column)
+		case clause.AndConditions:
+			conditionColumns(expr.Exprs, columns)
+		case clause.OrConditions:
+			conditionColumns(expr.Exprs, columns)
+		case clause.Expr:
+			for _, matches := range conditionColumnRegexp.FindAllStringSubmatch(expr.SQL, -1) {
+				columns[matches[1]] = true
+			}
+		case clause.NamedExpr:
+			for _, matches := range conditionColumnRegexp.FindAllStringSubmatch(expr.SQL, -1) {
+				columns[matches[1]] = true
+			}
+		}
+	}
+}
+
+var (
+	stringLiteralRegexp = regexp.MustCompile(`'(?:[^']|'')*'`)
+	placeholderRegexp   = regexp.MustCompile(`\$\d+|@p\d+|:\w+|\b\d+(?:\.\d+)?\b`)
+	placeholderList     = regexp.MustCompile(`\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+)
+
+// normalize replaces literals and placeholders of sql with ?, placeholder lists are collapsed to (?)
+func normalize(sql string) string {
+	sql = stringLiteralRegexp.ReplaceAllString(sql, "?")
+	sql = placeholderRegexp.ReplaceAllString(sql, "?")
+	return placeholderList.ReplaceAllString(sql, "(?)")
+}
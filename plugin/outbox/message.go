@@ -0,0 +1,108 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidLimit limit of dispatched messages is not positive
+var ErrInvalidLimit = errors.New("outbox: limit should be positive")
+
+// Emitter models emitting domain events, events of created, updated and deleted values are written to the outbox
+// in the same transaction, Events should only return events not emitted yet
+type Emitter interface {
+	Events() []interface{}
+}
+
+// Typer events having types, the Go type of an event is its type by default, e.g. `events.UserCreated`
+type Typer interface {
+	EventType() string
+}
+
+// Message outbox message of an event, Payload is encoded by the serializer of the outbox
+type Message struct {
+	ID           uint64 `gorm:"primaryKey"`
+	Type         string `gorm:"size:256"`
+	Table        string `gorm:"size:128"`
+	PrimaryKey   string `gorm:"size:256"`
+	Payload      []byte
+	CreatedAt    time.Time
+	DispatchedAt *time.Time `gorm:"index"`
+}
+
+// Dispatch claims at most limit undispatched messages in a transaction, messages claimed by other transactions are
+// skipped with `FOR UPDATE SKIP LOCKED`, the claimed messages are marked dispatched if fc succeeds, otherwise they are
+// released to be claimed again, messages are at least once dispatched as fc could be called again after failed commits
+func (o *Outbox) Dispatch(db *gorm.DB, limit int, fc func(messages []Message) error) (dispatched int, err error) {
+	if limit <= 0 {
+		return 0, ErrInvalidLimit
+	}
+
+	err = db.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) error {
+		var messages []Message
+		if err := tx.Table(o.tableName()).Clauses(clause.Locking{
+			Strength: clause.LockingStrengthUpdate,
+			Options:  clause.LockingOptionsSkipLocked,
+		}).Where("dispatched_at IS NULL").Order("id").Limit(limit).Find(&messages).Error; err != nil || len(messages) == 0 {
+			return err
+		}
+
+		if err := fc(messages); err != nil {
+			return err
+		}
+
+		ids := make([]uint64, len(messages))
+		for idx, message := range messages {
+			ids[idx] = message.ID
+		}
+
+		if err := tx.Table(o.tableName()).Where("id IN ?", ids).Update("dispatched_at", tx.NowFunc()).Error; err != nil {
+			return err
+		}
+		dispatched = len(messages)
+		return nil
+	})
+
+	if err != nil {
+		dispatched = 0
+	}
+	return dispatched, err
+}
+
+// Poll dispatches messages with fc until ctx is done or dispatching fails, it waits interval when no more messages
+// are claimable
+//
+//	go outbox.Poll(ctx, db, time.Second, 100, func(messages []outbox.Message) error {
+//	  return publisher.Publish(ctx, messages)
+//	})
+func (o *Outbox) Poll(ctx context.Context, db *gorm.DB, interval time.Duration, limit int, fc func(messages []Message) error) error {
+	if limit <= 0 {
+		return ErrInvalidLimit
+	}
+
+	db = db.WithContext(ctx)
+	for {
+		dispatched, err := o.Dispatch(db, limit, fc)
+		if err != nil {
+			// drivers may return their own errors of interrupted statements
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return err
+		}
+
+		if dispatched < limit {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			case <-timer.C:
+			}
+		}
+	}
+}
@@ -0,0 +1,149 @@
+// Package outbox provides a plugin writing domain events of models to an outbox table in the transactions changing
+// them, and dispatching them later, events are published reliably without writing to databases and brokers both.
+package outbox
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Outbox transactional outbox plugin, events of Emitter values created, updated or deleted by statements are encoded
+// with the registered serializer and written to Table, "outbox_messages" by default, in the transactions of the
+// statements, the table could be created with
+//
+//	db.Table("outbox_messages").AutoMigrate(&outbox.Message{})
+//
+// Events are written in default transactions of statements or transactions the statements are in, they are not
+// atomic with changes if SkipDefaultTransaction is enabled out of transactions
+type Outbox struct {
+	// Table outbox table
+	Table string
+	// Serializer name of the registered serializer encoding events, "json" by default
+	Serializer string
+
+	serializer schema.SerializerInterface
+}
+
+// Name plugin name
+func (o *Outbox) Name() string {
+	return "gorm:outbox"
+}
+
+// Initialize register callbacks writing events
+func (o *Outbox) Initialize(db *gorm.DB) error {
+	if o.Serializer == "" {
+		o.Serializer = "json"
+	}
+
+	serializer, ok := schema.GetSerializer(o.Serializer)
+	if !ok {
+		return fmt.Errorf("outbox: invalid serializer %v", o.Serializer)
+	}
+	o.serializer = serializer
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("gorm:after_create").Before("gorm:commit_or_rollback_transaction").Register("gorm:outbox", o.write),
+		callbacks.Update().After("gorm:after_update").Before("gorm:commit_or_rollback_transaction").Register("gorm:outbox", o.write),
+		callbacks.Delete().After("gorm:after_delete").Before("gorm:commit_or_rollback_transaction").Register("gorm:outbox", o.write),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) tableName() string {
+	if o.Table == "" {
+		return "outbox_messages"
+	}
+	return o.Table
+}
+
+// write writes events of the statement values to the outbox
+func (o *Outbox) write(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Table == o.tableName() {
+		return
+	}
+
+	var messages []Message
+	collect := func(value reflect.Value) {
+		if value.Kind() != reflect.Ptr && value.CanAddr() {
+			value = value.Addr()
+		} else if value.Kind() == reflect.Ptr && value.IsNil() {
+			return
+		}
+
+		emitter, ok := value.Interface().(Emitter)
+		if !ok {
+			return
+		}
+
+		for _, event := range emitter.Events() {
+			message, err := o.newMessage(db, reflect.Indirect(value), event)
+			if db.AddError(err) != nil {
+				return
+			}
+			messages = append(messages, message)
+		}
+	}
+
+	switch value := reflect.Indirect(db.Statement.ReflectValue); value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collect(value.Index(i))
+		}
+	case reflect.Struct:
+		collect(value)
+	}
+
+	if db.Error == nil && len(messages) > 0 {
+		db.AddError(db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(o.tableName()).Create(&messages).Error)
+	}
+}
+
+func (o *Outbox) newMessage(db *gorm.DB, value reflect.Value, event interface{}) (Message, error) {
+	message := Message{Table: db.Statement.Table, PrimaryKey: primaryKey(db.Statement, value)}
+	if typer, ok := event.(Typer); ok {
+		message.Type = typer.EventType()
+	} else if event != nil {
+		message.Type = reflect.Indirect(reflect.ValueOf(event)).Type().String()
+	}
+
+	field := &schema.Field{Name: "Payload", DBName: "payload", TagSettings: map[string]string{}}
+	payload, err := o.serializer.Value(db.Statement.Context, field, reflect.ValueOf(&message), event)
+	if err != nil {
+		return message, err
+	}
+
+	switch payload := payload.(type) {
+	case []byte:
+		message.Payload = payload
+	case string:
+		message.Payload = []byte(payload)
+	case nil:
+	default:
+		return message, fmt.Errorf("outbox: unsupported payload %T of serializer %v", payload, o.Serializer)
+	}
+	return message, nil
+}
+
+// primaryKey returns primary key values of value joined with ","
+func primaryKey(stmt *gorm.Statement, value reflect.Value) string {
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	values := make([]string, 0, len(stmt.Schema.PrimaryFields))
+	for _, field := range stmt.Schema.PrimaryFields {
+		if fieldValue, isZero := field.ValueOf(stmt.Context, value); !isZero {
+			values = append(values, fmt.Sprint(fieldValue))
+		}
+	}
+	return strings.Join(values, ",")
+}
@@ -0,0 +1,105 @@
+package resolver
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Pool connection pool of a source or replica, it counts statements in flight
+type Pool struct {
+	gorm.ConnPool
+	inFlight int64
+}
+
+// InFlight returns the number of statements in flight, queries are counted until their rows are returned by the
+// connection pool rather than until they are closed, as *sql.Rows can't be wrapped, so rows being read like ones of
+// Rows and FindInBatches are not counted
+func (pool *Pool) InFlight() int64 {
+	return atomic.LoadInt64(&pool.inFlight)
+}
+
+func (pool *Pool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return pool.ConnPool.PrepareContext(ctx, query)
+}
+
+func (pool *Pool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	atomic.AddInt64(&pool.inFlight, 1)
+	defer atomic.AddInt64(&pool.inFlight, -1)
+	return pool.ConnPool.ExecContext(ctx, query, args...)
+}
+
+func (pool *Pool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	atomic.AddInt64(&pool.inFlight, 1)
+	defer atomic.AddInt64(&pool.inFlight, -1)
+	return pool.ConnPool.QueryContext(ctx, query, args...)
+}
+
+func (pool *Pool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	atomic.AddInt64(&pool.inFlight, 1)
+	defer atomic.AddInt64(&pool.inFlight, -1)
+	return pool.ConnPool.QueryRowContext(ctx, query, args...)
+}
+
+// BeginTx begin a transaction on the pool
+func (pool *Pool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	switch beginner := pool.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err := beginner.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
+	case gorm.ConnPoolBeginner:
+		return beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+}
+
+// GetDBConn returns the *sql.DB of the pool
+func (pool *Pool) GetDBConn() (*sql.DB, error) {
+	switch connPool := pool.ConnPool.(type) {
+	case *sql.DB:
+		return connPool, nil
+	case gorm.GetDBConnector:
+		return connPool.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// connPool connection pool of the db, statements not routed by callbacks are executed on sources, e.g. migrations
+type connPool struct {
+	resolver *Resolver
+}
+
+func (pool *connPool) source() *Pool {
+	return pool.resolver.Policy.Resolve(pool.resolver.sources)
+}
+
+func (pool *connPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return pool.source().PrepareContext(ctx, query)
+}
+
+func (pool *connPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return pool.source().ExecContext(ctx, query, args...)
+}
+
+func (pool *connPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return pool.source().QueryContext(ctx, query, args...)
+}
+
+func (pool *connPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return pool.source().QueryRowContext(ctx, query, args...)
+}
+
+func (pool *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return pool.source().BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the *sql.DB of the first source
+func (pool *connPool) GetDBConn() (*sql.DB, error) {
+	return pool.resolver.sources[0].GetDBConn()
+}
@@ -0,0 +1,46 @@
+package resolver
+
+import (
+	"math/rand"
+	"sync/atomic"
+)
+
+// Policy load balancing policy, resolves the pool executing a statement from pools
+type Policy interface {
+	Resolve(pools []*Pool) *Pool
+}
+
+// RandomPolicy resolves pools randomly
+type RandomPolicy struct{}
+
+// Resolve resolve a random pool
+func (RandomPolicy) Resolve(pools []*Pool) *Pool {
+	if len(pools) == 1 {
+		return pools[0]
+	}
+	return pools[rand.Intn(len(pools))]
+}
+
+// RoundRobinPolicy resolves pools in turn
+type RoundRobinPolicy struct {
+	next uint64
+}
+
+// Resolve resolve the next pool
+func (policy *RoundRobinPolicy) Resolve(pools []*Pool) *Pool {
+	return pools[(atomic.AddUint64(&policy.next, 1)-1)%uint64(len(pools))]
+}
+
+// LeastInFlightPolicy resolves the pool with the least statements in flight, see Pool.InFlight for statements counted
+type LeastInFlightPolicy struct{}
+
+// Resolve resolve the least busy pool
+func (LeastInFlightPolicy) Resolve(pools []*Pool) *Pool {
+	resolved := pools[0]
+	for _, pool := range pools[1:] {
+		if pool.InFlight() < resolved.InFlight() {
+			resolved = pool
+		}
+	}
+	return resolved
+}
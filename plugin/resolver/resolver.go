@@ -0,0 +1,161 @@
+// Package resolver provides a read/write splitting plugin for GORM.
+package resolver
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Resolver read/write splitting plugin, queries are routed to replicas, other statements, locking queries and
+// transactions are routed to sources
+//
+//	db.Use(&resolver.Resolver{
+//	  Replicas: []gorm.ConnPool{replicaDB1, replicaDB2},
+//	  Policy:   &resolver.RoundRobinPolicy{},
+//	})
+type Resolver struct {
+	// Sources connection pools of sources, the connection pool of the db is used if it is empty
+	Sources []gorm.ConnPool
+	// Replicas connection pools of replicas, sources are used if it is empty
+	Replicas []gorm.ConnPool
+	// Policy load balancing policy, RandomPolicy is used if it is nil
+	Policy Policy
+	// ReadYourWritesWindow queries of a context returned by ReadYourWrites are routed to sources for the window after
+	// its writes, or until the context is done if the window is 0
+	ReadYourWritesWindow time.Duration
+
+	sources  []*Pool
+	replicas []*Pool
+}
+
+// Operation target of statements, could be used as clauses to override the target of a statement
+//
+//	db.Clauses(resolver.UseSource()).First(&user)
+type Operation string
+
+const (
+	// Source statements are executed on sources
+	Source Operation = "source"
+	// Replica statements are executed on replicas
+	Replica Operation = "replica"
+)
+
+const operationKey = "gorm:resolver:operation"
+
+// UseSource execute the statement on sources
+func UseSource() Operation {
+	return Source
+}
+
+// UseReplica execute the statement on replicas
+func UseReplica() Operation {
+	return Replica
+}
+
+// ModifyStatement modify operation mode
+func (op Operation) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Settings.Store(operationKey, op)
+}
+
+// Build implements clause.Expression interface
+func (op Operation) Build(clause.Builder) {
+}
+
+type writesTracker struct {
+	lastWrite int64
+}
+
+type writesTrackerKey struct{}
+
+// ReadYourWrites returns a context tracking its writes, queries of the context are routed to sources after writes,
+// see Resolver.ReadYourWritesWindow
+func ReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writesTrackerKey{}, &writesTracker{})
+}
+
+// Name plugin name
+func (r *Resolver) Name() string {
+	return "gorm:resolver"
+}
+
+// Initialize register callbacks routing statements and replace the connection pool of db
+func (r *Resolver) Initialize(db *gorm.DB) error {
+	sources := r.Sources
+	if len(sources) == 0 {
+		sources = []gorm.ConnPool{db.ConnPool}
+	}
+
+	r.sources = make([]*Pool, len(sources))
+	for idx, connPool := range sources {
+		r.sources[idx] = &Pool{ConnPool: connPool}
+	}
+
+	r.replicas = r.sources
+	if len(r.Replicas) > 0 {
+		r.replicas = make([]*Pool, len(r.Replicas))
+		for idx, connPool := range r.Replicas {
+			r.replicas[idx] = &Pool{ConnPool: connPool}
+		}
+	}
+
+	if r.Policy == nil {
+		r.Policy = RandomPolicy{}
+	}
+
+	pool := &connPool{resolver: r}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("gorm:resolver", r.switchPool(Source)),
+		callbacks.Query().Before("*").Register("gorm:resolver", r.switchPool(Replica)),
+		callbacks.Update().Before("*").Register("gorm:resolver", r.switchPool(Source)),
+		callbacks.Delete().Before("*").Register("gorm:resolver", r.switchPool(Source)),
+		callbacks.Row().Before("*").Register("gorm:resolver", r.switchPool(Replica)),
+		callbacks.Raw().Before("*").Register("gorm:resolver", r.switchPool(Source)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) switchPool(operation Operation) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		// writes in transactions are tracked as well, they are visible to queries of sources once committed
+		tracker, _ := db.Statement.Context.Value(writesTrackerKey{}).(*writesTracker)
+		if operation == Source && tracker != nil {
+			atomic.StoreInt64(&tracker.lastWrite, time.Now().UnixNano())
+		}
+
+		// statements in transactions or with customized connection pools are not routed
+		if _, ok := db.Statement.ConnPool.(*connPool); !ok {
+			return
+		}
+
+		target := operation
+		if v, ok := db.Statement.Settings.Load(operationKey); ok {
+			target, _ = v.(Operation)
+		} else if _, locking := db.Statement.Clauses["FOR"]; locking {
+			// locking reads lock rows of sources
+			target = Source
+		} else if target == Replica && tracker != nil {
+			if lastWrite := atomic.LoadInt64(&tracker.lastWrite); lastWrite > 0 &&
+				(r.ReadYourWritesWindow <= 0 || time.Since(time.Unix(0, lastWrite)) < r.ReadYourWritesWindow) {
+				target = Source
+			}
+		}
+
+		if target == Replica {
+			db.Statement.ConnPool = r.Policy.Resolve(r.replicas)
+		} else {
+			db.Statement.ConnPool = r.Policy.Resolve(r.sources)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package sharding
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+)
+
+// Algorithm routes shard key values to shards
+type Algorithm interface {
+	// Shards returns the number of shards
+	Shards() int
+	// Shard returns the shard of value, it should be in [0, Shards())
+	Shard(value interface{}) (int, error)
+}
+
+// Hash routes integers by modulo and other values by fnv hash modulo Count
+type Hash struct {
+	Count int
+}
+
+// Shards returns Count
+func (h Hash) Shards() int {
+	return h.Count
+}
+
+// Shard returns the shard of value
+func (h Hash) Shard(value interface{}) (int, error) {
+	if v, ok := toInt64(value); ok {
+		// reduced before negated as -math.MinInt64 overflows
+		shard := v % int64(h.Count)
+		if shard < 0 {
+			shard = -shard
+		}
+		return int(shard), nil
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	if !rv.IsValid() {
+		return 0, fmt.Errorf("%w: nil value", ErrInvalidShardKey)
+	}
+
+	hash := fnv.New32a()
+	fmt.Fprint(hash, rv.Interface())
+	return int(hash.Sum32() % uint32(h.Count)), nil
+}
+
+// Range routes integers by ranges, values less than Bounds[0] are in shard 0, values in [Bounds[i-1], Bounds[i]) are
+// in shard i, values not less than the last bound are in the last shard
+type Range struct {
+	Bounds []int64
+}
+
+// Shards returns the number of ranges
+func (r Range) Shards() int {
+	return len(r.Bounds) + 1
+}
+
+// Shard returns the shard of value
+func (r Range) Shard(value interface{}) (int, error) {
+	v, ok := toInt64(value)
+	if !ok {
+		return 0, fmt.Errorf("%w: range sharding requires integers, got %T", ErrInvalidShardKey, value)
+	}
+
+	return sort.Search(len(r.Bounds), func(i int) bool { return v < r.Bounds[i] }), nil
+}
+
+// Custom routes values with Func
+type Custom struct {
+	Count int
+	Func  func(value interface{}) (int, error)
+}
+
+// Shards returns Count
+func (c Custom) Shards() int {
+	return c.Count
+}
+
+// Shard returns the shard of value
+func (c Custom) Shard(value interface{}) (int, error) {
+	return c.Func(value)
+}
+
+func toInt64(value interface{}) (int64, bool) {
+	rv := reflect.Indirect(reflect.ValueOf(value))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
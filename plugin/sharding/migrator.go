@@ -0,0 +1,111 @@
+package sharding
+
+import (
+	"gorm.io/gorm"
+)
+
+// dialector creates tables of shards with its migrator
+type dialector struct {
+	gorm.Dialector
+	sharding *Sharding
+}
+
+func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return migrator{Migrator: d.Dialector.Migrator(db), db: db, dialector: d}
+}
+
+func (d dialector) SavePoint(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.SavePoint(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+func (d dialector) RollbackTo(tx *gorm.DB, name string) error {
+	if savePointer, ok := d.Dialector.(gorm.SavePointerDialectorInterface); ok {
+		return savePointer.RollbackTo(tx, name)
+	}
+	return gorm.ErrUnsupportedDriver
+}
+
+func (d dialector) Translate(err error) error {
+	if translator, ok := d.Dialector.(gorm.ErrorTranslator); ok {
+		return translator.Translate(err)
+	}
+	return err
+}
+
+// migrator migrates tables of all shards for tables having sharding rules
+type migrator struct {
+	gorm.Migrator
+	db        *gorm.DB
+	dialector dialector
+}
+
+// AutoMigrate migrates tables of all shards
+func (m migrator) AutoMigrate(values ...interface{}) error {
+	for _, value := range values {
+		if err := m.runOnShards(value, func(migrator gorm.Migrator, value interface{}) error {
+			return migrator.AutoMigrate(value)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropTable drops tables of all shards
+func (m migrator) DropTable(values ...interface{}) error {
+	for _, value := range values {
+		if err := m.runOnShards(value, func(migrator gorm.Migrator, value interface{}) error {
+			return migrator.DropTable(value)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runOnShards runs fc with migrators and values of all shards if the table of value has a sharding rule, otherwise fc
+// is run with the migrator of db
+func (m migrator) runOnShards(value interface{}, fc func(migrator gorm.Migrator, value interface{}) error) error {
+	table := m.db.Statement.Table
+	if table == "" {
+		if name, ok := value.(string); ok {
+			table = name
+		} else {
+			stmt := &gorm.Statement{DB: m.db}
+			if err := stmt.Parse(value); err != nil {
+				return err
+			}
+			table = stmt.Schema.Table
+		}
+	}
+
+	rule, ok := m.dialector.sharding.Rules[table]
+	if !ok {
+		return fc(m.Migrator, value)
+	}
+
+	// relationships aren't migrated for shards, as referenced tables are sharded or not in the same database
+	config := *m.db.Config
+	config.IgnoreRelationshipsWhenMigrating = true
+
+	for shard := 0; shard < rule.Algorithm.Shards(); shard++ {
+		tx := m.db.Table(rule.TableName(table, shard))
+		tx.Config = &config
+		if len(rule.ConnPools) > 0 {
+			tx.Statement.ConnPool = rule.ConnPools[shard%len(rule.ConnPools)]
+		}
+
+		shardValue := value
+		if _, ok := value.(string); ok {
+			shardValue = tx.Statement.Table
+		}
+
+		if err := fc(m.dialector.Dialector.Migrator(tx), shardValue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
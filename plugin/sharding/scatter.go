@@ -0,0 +1,325 @@
+package sharding
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// scatter routes queries with shard keys, queries without shard keys are executed on all shards, their results are
+// merged, sorted by order columns and limited by the LIMIT clause, numeric results of COUNT and SUM are summed
+func (s *Sharding) scatter(query func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		rule, table, ok := s.rule(db)
+		if !ok || db.Error != nil {
+			query(db)
+			return
+		}
+
+		if shard, found := s.shardOf(db, rule, true); found {
+			s.use(db, rule, table, shard)
+			query(db)
+		} else if db.Error == nil {
+			if db.DryRun {
+				// the SQL of the first shard is built for dry runs
+				s.use(db, rule, table, 0)
+				query(db)
+			} else {
+				s.gather(db, rule)
+			}
+		}
+	}
+}
+
+// gather executes the query on all shards and merges their results
+func (s *Sharding) gather(db *gorm.DB, rule Rule) {
+	reflectValue := db.Statement.ReflectValue
+
+	var resultType reflect.Type
+	switch reflectValue.Kind() {
+	case reflect.Slice:
+		resultType = reflectValue.Type()
+	case reflect.Struct:
+		resultType = reflect.SliceOf(reflectValue.Type())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		resultType = reflectValue.Type()
+	default:
+		db.AddError(fmt.Errorf("%w: unsupported destination %s for queries on all shards", ErrMissingShardKey, reflectValue.Type()))
+		return
+	}
+
+	if resultType.Kind() != reflect.Slice && !summable(db.Statement) {
+		db.AddError(fmt.Errorf("%w: only results of COUNT and SUM could be merged for queries on all shards", ErrMissingShardKey))
+		return
+	}
+
+	limit, _ := db.Statement.Clauses["LIMIT"].Expression.(clause.Limit)
+	shardLimit := clause.Limit{}
+	if limit.Limit != nil && *limit.Limit >= 0 {
+		n := *limit.Limit + limit.Offset
+		shardLimit.Limit = &n
+	}
+
+	var (
+		results reflect.Value
+		sum     = reflect.New(resultType).Elem()
+	)
+	if resultType.Kind() == reflect.Slice {
+		results = reflect.MakeSlice(resultType, 0, 0)
+	}
+
+	for shard := 0; shard < rule.Algorithm.Shards(); shard++ {
+		// numeric results of shards without rows could be NULL
+		var dest reflect.Value
+		switch resultType.Kind() {
+		case reflect.Slice:
+			dest = reflect.New(resultType)
+		case reflect.Float32, reflect.Float64:
+			dest = reflect.ValueOf(&sql.NullFloat64{})
+		default:
+			dest = reflect.ValueOf(&sql.NullInt64{})
+		}
+
+		tx := db.Session(&gorm.Session{SkipHooks: true}).Clauses(UseShard(shard))
+		tx.Statement.Dest = dest.Interface()
+		tx.Statement.Preloads = map[string][]interface{}{}
+		tx.Statement.RaiseErrorOnNotFound = false
+		if c, ok := tx.Statement.Clauses["LIMIT"]; ok {
+			c.Expression = shardLimit
+			tx.Statement.Clauses["LIMIT"] = c
+		}
+
+		if err := db.Callback().Query().Execute(tx).Error; err != nil {
+			db.AddError(err)
+			return
+		}
+
+		switch resultType.Kind() {
+		case reflect.Slice:
+			results = reflect.AppendSlice(results, dest.Elem())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			sum.SetInt(sum.Int() + dest.Interface().(*sql.NullInt64).Int64)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			sum.SetUint(sum.Uint() + uint64(dest.Interface().(*sql.NullInt64).Int64))
+		case reflect.Float32, reflect.Float64:
+			sum.SetFloat(sum.Float() + dest.Interface().(*sql.NullFloat64).Float64)
+		}
+	}
+
+	if resultType.Kind() != reflect.Slice {
+		// numeric results are merged into a row
+		reflectValue.Set(sum)
+		db.RowsAffected = 1
+		return
+	}
+
+	sortResults(db, results)
+
+	if offset := limit.Offset; offset > 0 {
+		if offset > results.Len() {
+			offset = results.Len()
+		}
+		results = results.Slice(offset, results.Len())
+	}
+
+	if shardLimit.Limit != nil && *limit.Limit < results.Len() {
+		results = results.Slice(0, *limit.Limit)
+	}
+
+	db.RowsAffected = int64(results.Len())
+	if reflectValue.Kind() == reflect.Slice {
+		reflectValue.Set(results)
+	} else if results.Len() > 0 {
+		reflectValue.Set(results.Index(0))
+	} else if db.Statement.RaiseErrorOnNotFound {
+		db.AddError(gorm.ErrRecordNotFound)
+	}
+}
+
+// summable reports whether numeric results of the statement are sums of results of shards, results of other
+// aggregates like MAX, MIN and AVG can't be merged
+func summable(stmt *gorm.Statement) bool {
+	var sql string
+	if c, ok := stmt.Clauses["SELECT"]; ok {
+		if expr, ok := c.Expression.(clause.Expr); ok {
+			sql = expr.SQL
+		}
+	}
+
+	if sql == "" {
+		sql = strings.Join(stmt.Selects, ",")
+	}
+
+	sql = strings.ToLower(strings.Join(strings.Fields(sql), ""))
+	return (strings.HasPrefix(sql, "count(") || strings.HasPrefix(sql, "sum(")) && !strings.Contains(sql, "distinct")
+}
+
+type orderColumn struct {
+	name string
+	desc bool
+}
+
+// sortResults sorts merged results by order columns, results are not sorted if they are ordered by expressions
+func sortResults(db *gorm.DB, results reflect.Value) {
+	orderBy, ok := db.Statement.Clauses["ORDER BY"].Expression.(clause.OrderBy)
+	if !ok || orderBy.Expression != nil {
+		return
+	}
+
+	var columns []orderColumn
+	for _, column := range orderBy.Columns {
+		if !column.Column.Raw {
+			name := column.Column.Name
+			if name == clause.PrimaryKey {
+				// First and Last order by primary keys
+				if db.Statement.Schema == nil || db.Statement.Schema.PrioritizedPrimaryField == nil {
+					return
+				}
+				name = db.Statement.Schema.PrioritizedPrimaryField.DBName
+			}
+			columns = append(columns, orderColumn{name: name, desc: column.Desc})
+			continue
+		}
+
+		for _, order := range strings.Split(column.Column.Name, ",") {
+			fields := strings.Fields(order)
+			if len(fields) == 0 || len(fields) > 2 {
+				return
+			}
+
+			name := fields[0]
+			if idx := strings.LastIndexByte(name, '.'); idx >= 0 {
+				name = name[idx+1:]
+			}
+			columns = append(columns, orderColumn{name: strings.Trim(name, "`\"[]"), desc: len(fields) == 2 && strings.EqualFold(fields[1], "desc")})
+		}
+	}
+
+	if len(columns) == 0 {
+		return
+	}
+
+	var elemSchema *schema.Schema
+	elemType := results.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+
+	if elemType.Kind() == reflect.Struct {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(reflect.New(elemType).Interface()); err == nil {
+			elemSchema = stmt.Schema
+		}
+	}
+
+	valueOf := func(elem reflect.Value, name string) interface{} {
+		elem = reflect.Indirect(elem)
+		switch elem.Kind() {
+		case reflect.Struct:
+			if elemSchema != nil {
+				if field := elemSchema.LookUpField(name); field != nil {
+					v, _ := field.ValueOf(db.Statement.Context, elem)
+					return v
+				}
+			}
+			return nil
+		case reflect.Map:
+			if v := elem.MapIndex(reflect.ValueOf(name)); v.IsValid() {
+				return v.Interface()
+			}
+			return nil
+		case reflect.Invalid:
+			return nil
+		default:
+			return elem.Interface()
+		}
+	}
+
+	sort.SliceStable(results.Interface(), func(i, j int) bool {
+		for _, column := range columns {
+			if result := compare(valueOf(results.Index(i), column.name), valueOf(results.Index(j), column.name)); result != 0 {
+				return (result < 0) != column.desc
+			}
+		}
+		return false
+	})
+}
+
+// compare compares values of the same column, nil values are less than others
+func compare(a, b interface{}) int {
+	if valuer, ok := a.(driver.Valuer); ok {
+		a, _ = valuer.Value()
+	}
+	if valuer, ok := b.(driver.Valuer); ok {
+		b, _ = valuer.Value()
+	}
+
+	ra, rb := reflect.Indirect(reflect.ValueOf(a)), reflect.Indirect(reflect.ValueOf(b))
+	switch {
+	case !ra.IsValid() && !rb.IsValid():
+		return 0
+	case !ra.IsValid():
+		return -1
+	case !rb.IsValid():
+		return 1
+	}
+
+	if ta, ok := ra.Interface().(time.Time); ok {
+		if tb, ok := rb.Interface().(time.Time); ok {
+			switch {
+			case ta.Before(tb):
+				return -1
+			case ta.After(tb):
+				return 1
+			}
+			return 0
+		}
+	}
+
+	switch ra.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rb.CanInt() {
+			return compareOrdered(ra.Int(), rb.Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rb.CanUint() {
+			return compareOrdered(ra.Uint(), rb.Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		if rb.CanFloat() {
+			return compareOrdered(ra.Float(), rb.Float())
+		}
+	case reflect.String:
+		if rb.Kind() == reflect.String {
+			return strings.Compare(ra.String(), rb.String())
+		}
+	case reflect.Bool:
+		if rb.Kind() == reflect.Bool && ra.Bool() != rb.Bool() {
+			if ra.Bool() {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,323 @@
+// Package sharding provides a horizontal sharding plugin for GORM.
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrMissingShardKey shard key is required by statements other than queries
+	ErrMissingShardKey = errors.New("shard key required")
+	// ErrInvalidShardKey shard key can't be routed
+	ErrInvalidShardKey = errors.New("invalid shard key")
+	// ErrMixedShards records of a statement are in different shards
+	ErrMixedShards = errors.New("records in different shards")
+)
+
+// Sharding horizontal sharding plugin, statements of tables having rules are routed to sharded tables by shard keys
+// taken from model values or AND conditions of WHERE, queries without shard keys or with OR conditions are executed
+// on all shards and their results are merged, other statements of them are rejected
+//
+// The table of a statement is renamed to the table of its shard, conditions should reference it with struct
+// conditions or clause.CurrentTable, joined tables having rules are aliased with their original names
+//
+//	db.Use(&sharding.Sharding{Rules: map[string]sharding.Rule{
+//	  "orders": {ShardKey: "tenant_id", Algorithm: sharding.Hash{Count: 16}},
+//	}})
+//
+//	db.AutoMigrate(&Order{}) // creates orders_0 ... orders_15
+//	db.Where("tenant_id = ?", 3).Find(&orders)
+//	// SELECT * FROM `orders_3` WHERE tenant_id = 3
+type Sharding struct {
+	// Rules sharding rules keyed by table names, tables joined together should be sharded by the same algorithm
+	Rules map[string]Rule
+}
+
+// Rule sharding rule of a table
+type Rule struct {
+	// ShardKey column name of the shard key
+	ShardKey string
+	// Algorithm routes shard keys to shards
+	Algorithm Algorithm
+	// TableFormat formats table names of shards with the table name and the shard, "%s_%d" by default
+	TableFormat string
+	// ConnPools connection pools of shards, shard i uses ConnPools[i%len(ConnPools)], the connection pool of the db is
+	// used if it is empty
+	ConnPools []gorm.ConnPool
+}
+
+// TableName returns the table name of shard
+func (rule Rule) TableName(table string, shard int) string {
+	if rule.TableFormat == "" {
+		return fmt.Sprintf("%s_%d", table, shard)
+	}
+	return fmt.Sprintf(rule.TableFormat, table, shard)
+}
+
+// Shard shard a statement is executed on, overrides the shard key
+//
+//	db.Clauses(sharding.UseShard(3)).Find(&orders)
+type Shard int
+
+const shardKey = "gorm:sharding:shard"
+
+// UseShard execute the statement on shard
+func UseShard(shard int) Shard {
+	return Shard(shard)
+}
+
+// ModifyStatement modify operation mode
+func (shard Shard) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Settings.Store(shardKey, int(shard))
+}
+
+// Build implements clause.Expression interface
+func (Shard) Build(clause.Builder) {
+}
+
+// Name plugin name
+func (s *Sharding) Name() string {
+	return "gorm:sharding"
+}
+
+// Initialize register callbacks routing statements and the migrator creating sharded tables
+func (s *Sharding) Initialize(db *gorm.DB) error {
+	for table, rule := range s.Rules {
+		if rule.ShardKey == "" || rule.Algorithm == nil || rule.Algorithm.Shards() <= 0 {
+			return fmt.Errorf("invalid sharding rule of table %s, shard key and algorithm required", table)
+		}
+	}
+
+	db.Dialector = dialector{Dialector: db.Dialector, sharding: s}
+
+	callbacks := db.Callback()
+	query, row := callbacks.Query().Get("gorm:query"), callbacks.Row().Get("gorm:row")
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("gorm:sharding", s.route),
+		callbacks.Query().Replace("gorm:query", s.scatter(query)),
+		callbacks.Update().Before("*").Register("gorm:sharding", s.route),
+		callbacks.Delete().Before("*").Register("gorm:sharding", s.route),
+		callbacks.Row().Replace("gorm:row", s.routeRow(row)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// route routes the statement to the shard of its shard key, ErrMissingShardKey is added if there is no shard key
+func (s *Sharding) route(db *gorm.DB) {
+	s.routeShard(db, false)
+}
+
+// routeRow routes row queries, they can't be executed on all shards
+func (s *Sharding) routeRow(row func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		s.routeShard(db, true)
+		row(db)
+	}
+}
+
+func (s *Sharding) routeShard(db *gorm.DB, query bool) {
+	if db.Error != nil {
+		return
+	}
+
+	if rule, table, ok := s.rule(db); ok {
+		if shard, found := s.shardOf(db, rule, query); found {
+			s.use(db, rule, table, shard)
+		} else if db.Error == nil {
+			db.AddError(fmt.Errorf("%w for table %s, shard key: %s", ErrMissingShardKey, table, rule.ShardKey))
+		}
+	}
+}
+
+// rule returns the sharding rule of the statement table, raw SQL is not routed
+func (s *Sharding) rule(db *gorm.DB) (rule Rule, table string, ok bool) {
+	if db.Statement.SQL.Len() > 0 || db.Statement.TableExpr != nil {
+		return rule, "", false
+	}
+
+	rule, ok = s.Rules[db.Statement.Table]
+	return rule, db.Statement.Table, ok
+}
+
+// shardOf returns the shard of the statement, values of slice models are not shard keys of queries
+func (s *Sharding) shardOf(db *gorm.DB, rule Rule, query bool) (shard int, found bool) {
+	if v, ok := db.Statement.Settings.Load(shardKey); ok {
+		shard, _ = v.(int)
+		return s.checkShard(db, rule, shard)
+	}
+
+	// conditions are preferred to model values, as query destinations may be reused
+	var values []interface{}
+	if where, ok := db.Statement.Clauses["WHERE"].Expression.(clause.Where); ok {
+		// records matching OR conditions may be in any shard, queries of them are executed on all shards
+		if hasOrConditions(where.Exprs) {
+			if !query {
+				db.AddError(fmt.Errorf("%w, conditions with OR can't be routed by shard key %s", ErrMissingShardKey, rule.ShardKey))
+			}
+			return 0, false
+		}
+		values = shardKeyValues(where.Exprs, rule.ShardKey)
+	}
+
+	if len(values) == 0 && db.Statement.Schema != nil && db.Statement.ReflectValue.IsValid() {
+		if field := db.Statement.Schema.LookUpField(rule.ShardKey); field != nil {
+			switch db.Statement.ReflectValue.Kind() {
+			case reflect.Slice, reflect.Array:
+				if !query {
+					for i := 0; i < db.Statement.ReflectValue.Len(); i++ {
+						if v, isZero := field.ValueOf(db.Statement.Context, reflect.Indirect(db.Statement.ReflectValue.Index(i))); !isZero {
+							values = append(values, v)
+						}
+					}
+				}
+			case reflect.Struct:
+				if db.Statement.ReflectValue.Type() == db.Statement.Schema.ModelType {
+					if v, isZero := field.ValueOf(db.Statement.Context, db.Statement.ReflectValue); !isZero {
+						values = append(values, v)
+					}
+				}
+			}
+		}
+	}
+
+	for idx, value := range values {
+		v, err := rule.Algorithm.Shard(value)
+		if err != nil {
+			db.AddError(err)
+			return 0, false
+		}
+
+		if idx > 0 && v != shard {
+			db.AddError(fmt.Errorf("%w, shard key: %s", ErrMixedShards, rule.ShardKey))
+			return 0, false
+		}
+		shard = v
+	}
+
+	if len(values) == 0 {
+		return 0, false
+	}
+	return s.checkShard(db, rule, shard)
+}
+
+func (s *Sharding) checkShard(db *gorm.DB, rule Rule, shard int) (int, bool) {
+	if shard < 0 || shard >= rule.Algorithm.Shards() {
+		db.AddError(fmt.Errorf("%w: shard %d out of range", ErrInvalidShardKey, shard))
+		return 0, false
+	}
+	return shard, true
+}
+
+var shardKeyConditionRegexp = regexp.MustCompile("^\\s*(?:[`\"\\[]?\\w+[`\"\\]]?\\.)?[`\"\\[]?(\\w+)[`\"\\]]?\\s*=\\s*\\?\\s*$")
+
+// shardKeyValues returns values of the shard key in AND conditions
+func shardKeyValues(exprs []clause.Expression, key string) (values []interface{}) {
+	for _, expr := range exprs {
+		switch v := expr.(type) {
+		case clause.Eq:
+			if columnName(v.Column) == key {
+				values = append(values, v.Value)
+			}
+		case clause.IN:
+			if columnName(v.Column) == key && len(v.Values) == 1 {
+				values = append(values, v.Values[0])
+			}
+		case clause.Expr:
+			if matches := shardKeyConditionRegexp.FindStringSubmatch(v.SQL); len(matches) == 2 && matches[1] == key && len(v.Vars) == 1 {
+				values = append(values, v.Vars[0])
+			}
+		case clause.AndConditions:
+			values = append(values, shardKeyValues(v.Exprs, key)...)
+		}
+
+		if len(values) > 0 {
+			return values
+		}
+	}
+	return values
+}
+
+// hasOrConditions returns true if any of exprs or their AND conditions is an OR condition
+func hasOrConditions(exprs []clause.Expression) bool {
+	for _, expr := range exprs {
+		switch v := expr.(type) {
+		case clause.OrConditions:
+			return true
+		case clause.AndConditions:
+			if hasOrConditions(v.Exprs) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func columnName(column interface{}) string {
+	switch v := column.(type) {
+	case clause.Column:
+		return v.Name
+	case string:
+		if idx := strings.LastIndexByte(v, '.'); idx >= 0 {
+			return v[idx+1:]
+		}
+		return v
+	}
+	return ""
+}
+
+// use executes the statement on shard
+func (s *Sharding) use(db *gorm.DB, rule Rule, table string, shard int) {
+	db.Statement.Table = rule.TableName(table, shard)
+
+	if len(rule.ConnPools) > 0 {
+		if _, ok := db.Statement.ConnPool.(gorm.TxCommitter); !ok {
+			db.Statement.ConnPool = rule.ConnPools[shard%len(rule.ConnPools)]
+		}
+	}
+
+	// joined tables are routed to the same shard when the FROM clause is built
+	c, ok := db.Statement.Clauses["FROM"]
+	if !ok && len(db.Statement.Joins) == 0 {
+		return
+	}
+
+	c.Builder = func(c clause.Clause, builder clause.Builder) {
+		if from, ok := c.Expression.(clause.From); ok {
+			from.Tables = append([]clause.Table(nil), from.Tables...)
+			for idx, t := range from.Tables {
+				from.Tables[idx] = s.shardTable(t, shard)
+			}
+
+			from.Joins = append([]clause.Join(nil), from.Joins...)
+			for idx, join := range from.Joins {
+				from.Joins[idx].Table = s.shardTable(join.Table, shard)
+			}
+			c.Expression = from
+		}
+
+		c.Builder = nil
+		c.Build(builder)
+	}
+	db.Statement.Clauses["FROM"] = c
+}
+
+func (s *Sharding) shardTable(table clause.Table, shard int) clause.Table {
+	if rule, ok := s.Rules[table.Name]; ok && !table.Raw {
+		if table.Alias == "" {
+			table.Alias = table.Name
+		}
+		table.Name = rule.TableName(table.Name, shard)
+	}
+	return table
+}
@@ -0,0 +1,372 @@
+// Package tenancy provides a multi-tenancy plugin scoping statements of models to the tenant of their contexts.
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	// ErrMissingTenant statements of tenant scoped models require tenants in their contexts
+	ErrMissingTenant = errors.New("tenant required")
+	// ErrForeignTenant records of other tenants can't be written
+	ErrForeignTenant = errors.New("foreign tenant")
+)
+
+// TenantScoped models scoped by tenants, TenantColumn returns the column of their tenants, models can also be scoped
+// with the tag `gorm:"tenant"` of their tenant fields
+//
+//	type Order struct {
+//	  ID       uint
+//	  TenantID string `gorm:"tenant"`
+//	}
+type TenantScoped interface {
+	TenantColumn() string
+}
+
+// Strategy isolation strategy of tenants
+type Strategy int
+
+const (
+	// SharedTable tenants share tables, statements are scoped by tenant columns
+	SharedTable Strategy = iota
+	// SchemaPerTenant tenants have their own tables, tables of statements are prefixed with TablePrefix of their
+	// tenants like NamingStrategy.TablePrefix, e.g. `tenant_1.orders`
+	SchemaPerTenant
+)
+
+// Tenancy multi-tenancy plugin, statements of tenant scoped models are scoped to the tenant of their contexts, tenant
+// columns of created records are set to it and writes of records of other tenants are rejected with ErrForeignTenant
+//
+// Statements without tenants are rejected with ErrMissingTenant, they can be executed on all tenants explicitly with
+// CrossTenant, Unscoped doesn't affect tenants. Raw SQL, statements of tables without models and tables joined with
+// Joins are not scoped. Upserts only update conflicting records of the tenant with conditions of ON CONFLICT, which
+// requires dialects supporting them, e.g. ON DUPLICATE KEY UPDATE of MySQL is not conditional
+//
+//	db.Use(&tenancy.Tenancy{})
+//	ctx := tenancy.WithTenant(r.Context(), "acme")
+//	db.WithContext(ctx).Find(&orders)
+//	// SELECT * FROM `orders` WHERE `orders`.`tenant_id` = "acme"
+type Tenancy struct {
+	// Strategy isolation strategy of tenants, tenants share tables by default
+	Strategy Strategy
+	// TablePrefix returns the table prefix of tenant for SchemaPerTenant, `tenant + "."` by default
+	TablePrefix func(tenant interface{}) string
+	// Tenant returns the tenant of ctx, TenantFromContext is used by default
+	Tenant func(ctx context.Context) (interface{}, bool)
+
+	fields sync.Map // tenant fields of schemas
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a context of tenant
+func WithTenant(ctx context.Context, tenant interface{}) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of ctx
+func TenantFromContext(ctx context.Context) (interface{}, bool) {
+	tenant := ctx.Value(tenantKey{})
+	return tenant, tenant != nil
+}
+
+const crossTenantKey = "gorm:tenancy:cross_tenant"
+
+// AllTenants statement modifier executing statements on all tenants
+type AllTenants struct{}
+
+// CrossTenant executes statements on all tenants, escape hatch of tenant scopes
+//
+//	db.Clauses(tenancy.CrossTenant()).Find(&orders)
+func CrossTenant() AllTenants {
+	return AllTenants{}
+}
+
+// ModifyStatement modify operation mode
+func (AllTenants) ModifyStatement(stmt *gorm.Statement) {
+	stmt.Settings.Store(crossTenantKey, true)
+}
+
+// Build implements clause.Expression interface
+func (AllTenants) Build(clause.Builder) {
+}
+
+// Name plugin name
+func (t *Tenancy) Name() string {
+	return "gorm:tenancy"
+}
+
+// Initialize register callbacks scoping statements to tenants
+func (t *Tenancy) Initialize(db *gorm.DB) error {
+	if t.TablePrefix == nil {
+		t.TablePrefix = func(tenant interface{}) string {
+			return fmt.Sprintf("%v.", tenant)
+		}
+	}
+
+	if t.Tenant == nil {
+		t.Tenant = TenantFromContext
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("gorm:tenancy", t.create),
+		callbacks.Query().Before("gorm:query").Register("gorm:tenancy", t.query),
+		callbacks.Update().Before("gorm:update").Register("gorm:tenancy", t.update),
+		callbacks.Delete().Before("gorm:delete").Register("gorm:tenancy", t.delete),
+		callbacks.Row().Before("gorm:row").Register("gorm:tenancy", t.query),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Migrate migrates tables of models for tenant, tables are prefixed with TablePrefix for SchemaPerTenant
+func (t *Tenancy) Migrate(db *gorm.DB, tenant interface{}, models ...interface{}) error {
+	for _, model := range models {
+		tx := db.Session(&gorm.Session{NewDB: true})
+		if t.Strategy == SchemaPerTenant {
+			stmt := &gorm.Statement{DB: db}
+			if err := stmt.Parse(model); err != nil {
+				return err
+			}
+			tx = tx.Table(t.TablePrefix(tenant) + stmt.Table)
+		}
+
+		if err := tx.AutoMigrate(model); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// field returns the tenant field of sch, scoped is false if sch is not scoped by tenants
+func (t *Tenancy) field(sch *schema.Schema) (field *schema.Field, scoped bool) {
+	type tenantField struct {
+		field  *schema.Field
+		scoped bool
+	}
+
+	if v, ok := t.fields.Load(sch); ok {
+		return v.(tenantField).field, v.(tenantField).scoped
+	}
+
+	if scopedModel, ok := reflect.New(sch.ModelType).Interface().(TenantScoped); ok {
+		scoped = true
+		if column := scopedModel.TenantColumn(); column != "" {
+			field = sch.LookUpField(column)
+		}
+	} else {
+		for _, f := range sch.Fields {
+			if _, ok := f.TagSettings["TENANT"]; ok {
+				field, scoped = f, true
+				break
+			}
+		}
+	}
+
+	t.fields.Store(sch, tenantField{field: field, scoped: scoped})
+	return field, scoped
+}
+
+// scope returns the tenant and the tenant field of the statement, ok is false if the statement is not scoped, tables
+// of statements are prefixed for SchemaPerTenant
+func (t *Tenancy) scope(db *gorm.DB) (tenant interface{}, field *schema.Field, ok bool) {
+	stmt := db.Statement
+	if db.Error != nil || stmt.Schema == nil || stmt.SQL.Len() > 0 {
+		return nil, nil, false
+	}
+
+	if _, crossTenant := stmt.Settings.Load(crossTenantKey); crossTenant {
+		return nil, nil, false
+	}
+
+	field, scoped := t.field(stmt.Schema)
+	if !scoped {
+		return nil, nil, false
+	}
+
+	if tenant, ok = t.Tenant(stmt.Context); !ok {
+		db.AddError(fmt.Errorf("%w for table %s", ErrMissingTenant, stmt.Table))
+		return nil, nil, false
+	}
+
+	// tables are computed from schemas as statements of chains are executed more than once
+	if t.Strategy == SchemaPerTenant && stmt.TableExpr == nil {
+		stmt.Table = t.TablePrefix(tenant) + stmt.Schema.Table
+	}
+	return tenant, field, true
+}
+
+func (t *Tenancy) create(db *gorm.DB) {
+	if tenant, field, ok := t.scope(db); ok && field != nil {
+		t.assign(db, tenant, field, true)
+
+		// upserts like Save only update conflicting records of the tenant
+		if c, ok := db.Statement.Clauses["ON CONFLICT"]; ok {
+			if onConflict, _ := c.Expression.(clause.OnConflict); !onConflict.DoNothing && (onConflict.UpdateAll || len(onConflict.DoUpdates) > 0) {
+				onConflict.Where.Exprs = append(onConflict.Where.Exprs, clause.Eq{
+					Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenant,
+				})
+				db.Statement.AddClause(onConflict)
+			}
+		}
+	}
+}
+
+func (t *Tenancy) query(db *gorm.DB) {
+	if tenant, field, ok := t.scope(db); ok && field != nil {
+		where(db, tenant, field)
+	}
+}
+
+func (t *Tenancy) update(db *gorm.DB) {
+	if tenant, field, ok := t.scope(db); ok && field != nil {
+		if t.assign(db, tenant, field, false); conditional(db) {
+			where(db, tenant, field)
+		}
+	}
+}
+
+func (t *Tenancy) delete(db *gorm.DB) {
+	if tenant, field, ok := t.scope(db); ok && field != nil && conditional(db) {
+		where(db, tenant, field)
+	}
+}
+
+// where adds the tenant condition to the statement, current conditions having OR conditions are wrapped with
+// parentheses like conditions of soft delete, otherwise OR conditions would match records of other tenants
+func where(db *gorm.DB, tenant interface{}, field *schema.Field) {
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if conds, ok := c.Expression.(clause.Where); ok {
+			for _, expr := range conds.Exprs {
+				if _, ok := expr.(clause.OrConditions); ok {
+					c.Expression = clause.Where{Exprs: []clause.Expression{clause.And(conds.Exprs...)}}
+					db.Statement.Clauses["WHERE"] = c
+					break
+				}
+			}
+		}
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: tenant},
+	}})
+}
+
+// conditional returns true if the update or delete has conditions, gorm.ErrMissingWhereClause is added otherwise
+// as the tenant condition doesn't make statements conditional
+func conditional(db *gorm.DB) bool {
+	stmt := db.Statement
+	if db.AllowGlobalUpdate {
+		return true
+	}
+
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		where, _ := c.Expression.(clause.Where)
+		if _, withSoftDelete := stmt.Clauses["soft_delete_enabled"]; !withSoftDelete || len(where.Exprs) > 1 {
+			return true
+		}
+	}
+
+	// records of models are updated or deleted by primary keys
+	if _, values := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields); len(values) > 0 {
+		return true
+	}
+
+	db.AddError(gorm.ErrMissingWhereClause)
+	return false
+}
+
+// assign sets zero tenant fields of records of the statement to tenant, ErrForeignTenant is added if any of them
+// belongs to another tenant, tenant columns missing from created maps are set too
+func (t *Tenancy) assign(db *gorm.DB, tenant interface{}, field *schema.Field, create bool) {
+	stmt := db.Statement
+	check := func(value interface{}) bool {
+		if !sameTenant(value, tenant) {
+			db.AddError(fmt.Errorf("%w %v of table %s, tenant: %v", ErrForeignTenant, value, stmt.Table, tenant))
+			return false
+		}
+		return true
+	}
+
+	assignMap := func(values map[string]interface{}) {
+		for _, key := range []string{field.DBName, field.Name} {
+			if value, ok := values[key]; ok {
+				check(value)
+				return
+			}
+		}
+
+		if create {
+			values[field.DBName] = tenant
+		}
+	}
+
+	assignValue := func(value reflect.Value) bool {
+		if v, isZero := field.ValueOf(stmt.Context, value); !isZero {
+			return check(v)
+		}
+		return db.AddError(field.Set(stmt.Context, value, tenant)) == nil
+	}
+
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		assignMap(dest)
+	case *map[string]interface{}:
+		assignMap(*dest)
+	case []map[string]interface{}:
+		for _, values := range dest {
+			assignMap(values)
+		}
+	case *[]map[string]interface{}:
+		for _, values := range *dest {
+			assignMap(values)
+		}
+	default:
+		// updates of structs other than the model are converted to assignments of their fields
+		reflectValue := stmt.ReflectValue
+		if !create && stmt.Dest != stmt.Model {
+			destValue := reflect.Indirect(reflect.ValueOf(stmt.Dest))
+			if destValue.Kind() != reflect.Struct || destValue.Type() != stmt.Schema.ModelType {
+				return
+			}
+			reflectValue = destValue
+		}
+
+		switch reflectValue.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < reflectValue.Len(); i++ {
+				if elem := reflect.Indirect(reflectValue.Index(i)); elem.Kind() == reflect.Struct && !assignValue(elem) {
+					return
+				}
+			}
+		case reflect.Struct:
+			if reflectValue.CanAddr() {
+				assignValue(reflectValue)
+			} else if v, isZero := field.ValueOf(stmt.Context, reflectValue); !isZero {
+				check(v)
+			}
+		}
+	}
+}
+
+// sameTenant returns true if value is the tenant, values of different types like uint and int are compared by their
+// formatted values
+func sameTenant(value, tenant interface{}) bool {
+	v, ten := reflect.Indirect(reflect.ValueOf(value)), reflect.Indirect(reflect.ValueOf(tenant))
+	if !v.IsValid() || !ten.IsValid() {
+		return v.IsValid() == ten.IsValid()
+	}
+	return fmt.Sprint(v.Interface()) == fmt.Sprint(ten.Interface())
+}
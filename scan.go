@@ -1,8 +1,12 @@
 package gorm
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"strings"
 	"time"
@@ -85,7 +89,7 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 			for _, joinSchema := range nestedJoinSchemas {
 				fullRels = append(fullRels, joinSchema.Name)
 				relValue = joinSchema.ReflectValueOf(db.Statement.Context, currentReflectValue)
-				if relValue.Kind() == reflect.Ptr {
+				if kind := relValue.Kind(); kind == reflect.Ptr || kind == reflect.Slice {
 					fullRelsName := utils.JoinNestedRelationNames(fullRels)
 					// same nested structure
 					if _, ok := joinedNestedSchemaMap[fullRelsName]; !ok {
@@ -94,9 +98,20 @@ func (db *DB) scanIntoStruct(rows Rows, reflectValue reflect.Value, values []int
 							break
 						}
 
-						relValue.Set(reflect.New(relValue.Type().Elem()))
+						if kind == reflect.Ptr {
+							relValue.Set(reflect.New(relValue.Type().Elem()))
+						} else if elemType := relValue.Type().Elem(); elemType.Kind() == reflect.Ptr {
+							relValue.Set(reflect.Append(relValue, reflect.New(elemType.Elem())))
+						} else {
+							relValue.Set(reflect.Append(relValue, reflect.New(elemType).Elem()))
+						}
 						joinedNestedSchemaMap[fullRelsName] = nil
 					}
+
+					// a joined row of has many relations is scanned to the last element
+					if kind == reflect.Slice {
+						relValue = relValue.Index(relValue.Len() - 1)
+					}
 				}
 				currentReflectValue = relValue
 			}
@@ -191,6 +206,9 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 		var (
 			fields       = make([]*schema.Field, len(columns))
 			joinFields   [][]*schema.Field
+			joined       []*joinedRelation
+			aggregated   map[int]*schema.Relationship
+			mergeRows    bool // merge joined rows of has many relations to their parents
 			sch          = db.Statement.Schema
 			reflectValue = db.Statement.ReflectValue
 		)
@@ -227,7 +245,15 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 			if sch != nil {
 				matchedFieldCount := make(map[string]int, len(columns))
 				for idx, column := range columns {
-					if field := sch.LookUpField(column); field != nil && field.Readable {
+					if rel := sch.Relationships.Relations[column]; rel != nil && rel.Type == schema.HasMany {
+						// has many relations aggregated to JSON arrays by Joins
+						if aggregated == nil {
+							aggregated = map[int]*schema.Relationship{}
+						}
+						aggregated[idx] = rel
+						var val interface{}
+						values[idx] = &val
+					} else if field := sch.LookUpField(column); field != nil && field.Readable {
 						fields[idx] = field
 						if count, ok := matchedFieldCount[column]; ok {
 							// handle duplicate fields
@@ -258,9 +284,11 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 							// nested relation fields
 							relFields := make([]*schema.Field, 0, subNameCount-1)
 							relFields = append(relFields, rel.Field)
+							rels := []*schema.Relationship{rel}
 							for _, name := range names[1 : subNameCount-1] {
 								rel = rel.FieldSchema.Relationships.Relations[name]
 								relFields = append(relFields, rel.Field)
+								rels = append(rels, rel)
 							}
 							// latest name is raw dbname
 							dbName := names[subNameCount-1]
@@ -272,6 +300,11 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 								}
 								relFields = append(relFields, field)
 								joinFields[idx] = relFields
+
+								joined = addJoinedRelation(joined, rels)
+								for _, rel := range rels {
+									mergeRows = mergeRows || rel.Type == schema.HasMany
+								}
 								continue
 							}
 						}
@@ -290,6 +323,7 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 			var (
 				elem        reflect.Value
 				isArrayKind = reflectValue.Kind() == reflect.Array
+				parents     = map[string]int{}
 			)
 
 			if !update || reflectValue.Len() == 0 {
@@ -329,6 +363,18 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				}
 
 				db.scanIntoStruct(rows, elem, values, fields, joinFields)
+				db.scanAggregated(elem, values, aggregated)
+
+				if mergeRows && !update {
+					if key, ok := joinedRowKey(db.Statement.Context, sch, elem); ok {
+						if idx, loaded := parents[key]; loaded && idx < reflectValue.Len() {
+							db.RowsAffected--
+							db.mergeJoinedRows(reflectValue.Index(idx), elem, joined)
+							continue
+						}
+						parents[key] = int(db.RowsAffected - 1)
+					}
+				}
 
 				if !update {
 					if !isPtr {
@@ -348,11 +394,36 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 				db.Statement.ReflectValue.Set(reflectValue)
 			}
 		case reflect.Struct, reflect.Ptr:
+			// rows scanned by ScanRows are scanned one by one, as the following rows may belong to other records
+			mergeRows = mergeRows && !initialized
 			if initialized || rows.Next() {
 				if mode == ScanInitialized && reflectValue.Kind() == reflect.Struct {
 					db.Statement.ReflectValue.Set(reflect.Zero(reflectValue.Type()))
 				}
+				if mergeRows {
+					// has many relations are loaded by joined rows
+					for _, j := range joined {
+						if fieldValue := j.relationship.Field.ReflectValueOf(db.Statement.Context, reflectValue); fieldValue.Kind() == reflect.Slice {
+							fieldValue.Set(reflect.Zero(fieldValue.Type()))
+						}
+					}
+				}
+
 				db.scanIntoStruct(rows, reflectValue, values, fields, joinFields)
+				db.scanAggregated(reflectValue, values, aggregated)
+
+				if mergeRows {
+					key, _ := joinedRowKey(db.Statement.Context, sch, reflectValue)
+					for rows.Next() {
+						elem := reflect.New(reflectValueType)
+						db.scanIntoStruct(rows, elem, values, fields, joinFields)
+						db.RowsAffected--
+
+						if elemKey, ok := joinedRowKey(db.Statement.Context, sch, elem); ok && elemKey == key {
+							db.mergeJoinedRows(reflectValue, elem, joined)
+						}
+					}
+				}
 			}
 		default:
 			db.AddError(rows.Scan(dest))
@@ -367,3 +438,153 @@ func Scan(rows Rows, db *DB, mode ScanMode) {
 		db.AddError(ErrRecordNotFound)
 	}
 }
+
+// joinedRelation relationship loaded by Joins with its nested joined relationships
+type joinedRelation struct {
+	relationship *schema.Relationship
+	nested       []*joinedRelation
+}
+
+// addJoinedRelation adds the nested relationships path to joined
+func addJoinedRelation(joined []*joinedRelation, path []*schema.Relationship) []*joinedRelation {
+	if len(path) == 0 {
+		return joined
+	}
+
+	for _, j := range joined {
+		if j.relationship == path[0] {
+			j.nested = addJoinedRelation(j.nested, path[1:])
+			return joined
+		}
+	}
+	return append(joined, &joinedRelation{relationship: path[0], nested: addJoinedRelation(nil, path[1:])})
+}
+
+// joinedRowKey returns the key of primary values of value, returns false if sch has no primary keys or they are zero
+func joinedRowKey(ctx context.Context, sch *schema.Schema, value reflect.Value) (string, bool) {
+	if sch == nil || len(sch.PrimaryFields) == 0 {
+		return "", false
+	}
+
+	var (
+		values = make([]interface{}, len(sch.PrimaryFields))
+		loaded bool
+	)
+	for idx, field := range sch.PrimaryFields {
+		var isZero bool
+		values[idx], isZero = field.ValueOf(ctx, value)
+		loaded = loaded || !isZero
+	}
+	return utils.ToStringKey(values...), loaded
+}
+
+// mergeJoinedRows merges relations of the joined row src to dst, elements of has many relations are appended to dst
+// unless they are loaded by previous rows
+func (db *DB) mergeJoinedRows(dst, src reflect.Value, joined []*joinedRelation) {
+	ctx := db.Statement.Context
+	for _, j := range joined {
+		dstValue := j.relationship.Field.ReflectValueOf(ctx, dst)
+		srcValue := j.relationship.Field.ReflectValueOf(ctx, src)
+
+		switch srcValue.Kind() {
+		case reflect.Slice:
+		ELEMS:
+			for i := 0; i < srcValue.Len(); i++ {
+				elem := srcValue.Index(i)
+				if key, ok := joinedRowKey(ctx, j.relationship.FieldSchema, elem); ok {
+					// joined rows of the same element are usually adjacent
+					for k := dstValue.Len() - 1; k >= 0; k-- {
+						if loadedKey, _ := joinedRowKey(ctx, j.relationship.FieldSchema, dstValue.Index(k)); loadedKey == key {
+							db.mergeJoinedRows(dstValue.Index(k), elem, j.nested)
+							continue ELEMS
+						}
+					}
+				}
+				dstValue.Set(reflect.Append(dstValue, elem))
+			}
+		case reflect.Ptr:
+			if srcValue.IsNil() {
+				continue
+			} else if dstValue.IsNil() {
+				dstValue.Set(srcValue)
+			} else {
+				db.mergeJoinedRows(dstValue, srcValue, j.nested)
+			}
+		default:
+			db.mergeJoinedRows(dstValue, srcValue, j.nested)
+		}
+	}
+}
+
+// scanAggregated decodes JSON arrays of has many relations aggregated by Joins to reflectValue
+func (db *DB) scanAggregated(reflectValue reflect.Value, values []interface{}, aggregated map[int]*schema.Relationship) {
+	ctx := db.Statement.Context
+	for idx, rel := range aggregated {
+		var data []byte
+		switch v := (*values[idx].(*interface{})).(type) {
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		}
+
+		fieldValue := rel.Field.ReflectValueOf(ctx, reflectValue)
+		fieldValue.Set(reflect.MakeSlice(fieldValue.Type(), 0, 0))
+		if len(data) == 0 {
+			continue
+		}
+
+		var rows []map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&rows); err != nil {
+			db.AddError(fmt.Errorf("failed to decode aggregated %s, got error %w", rel.Name, err))
+			continue
+		}
+
+		elemType := fieldValue.Type().Elem()
+		for _, row := range rows {
+			elem := reflect.New(rel.FieldSchema.ModelType)
+			for column, value := range row {
+				if field := rel.FieldSchema.LookUpField(column); field != nil && field.Readable {
+					switch v := value.(type) {
+					case json.Number:
+						if i, err := v.Int64(); err == nil {
+							value = i
+						} else {
+							value, _ = v.Float64()
+						}
+					case string:
+						if field.DataType == schema.Time {
+							value = parseAggregatedTime(v)
+						}
+					}
+					db.AddError(field.Set(ctx, elem, value))
+				}
+			}
+
+			if elemType.Kind() != reflect.Ptr {
+				elem = elem.Elem()
+			}
+			fieldValue.Set(reflect.Append(fieldValue, elem))
+		}
+	}
+}
+
+// aggregatedTimeLayouts layouts of times in JSON arrays aggregated by databases
+var aggregatedTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// parseAggregatedTime parses the time of aggregated JSON arrays, returns value if it is not in known layouts
+func parseAggregatedTime(value string) interface{} {
+	for _, layout := range aggregatedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return value
+}
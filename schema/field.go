@@ -360,6 +360,15 @@ func (schema *Schema) ParseField(fieldStruct reflect.StructField) *Field {
 		}
 	}
 
+	// counts of associations loaded by PreloadCount aren't columns
+	if _, ok := field.TagSettings["COUNT"]; ok {
+		field.Creatable = false
+		field.Updatable = false
+		field.Readable = false
+		field.DataType = ""
+		field.IgnoreMigration = true
+	}
+
 	if v, ok := field.TagSettings["->"]; ok {
 		field.Creatable = false
 		field.Updatable = false
@@ -584,7 +593,7 @@ func (field *Field) setupValuerAndSetter(modelType reflect.Type) {
 				if v, err = valuer.Value(); err == nil {
 					err = setter(ctx, value, v)
 				}
-			} else if _, ok := v.(clause.Expr); !ok {
+			} else if _, ok := v.(clause.Expression); !ok {
 				return fmt.Errorf("failed to set value %#v to field %s", v, field.Name)
 			}
 		}
@@ -0,0 +1,16 @@
+module gorm.io/gorm/schema/protobuf
+
+go 1.23
+
+require (
+	google.golang.org/protobuf v1.36.12
+	gorm.io/gorm v1.31.0
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
+
+replace gorm.io/gorm => ../../
@@ -0,0 +1,83 @@
+// Package protobuf provides the protobuf serializer, it's a module of its own so the core module doesn't depend on
+// google.golang.org/protobuf, the serializer is registered as `protobuf` when the package is imported
+//
+//	import _ "gorm.io/gorm/schema/protobuf"
+package protobuf
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm/schema"
+)
+
+func init() {
+	schema.RegisterSerializer("protobuf", Serializer{})
+}
+
+// Serializer protobuf serializer of proto.Message fields, fields of it should use binary types
+//
+//	type Order struct {
+//	  Detail *pb.OrderDetail `gorm:"type:bytes;serializer:protobuf"`
+//	}
+//
+// nil messages are stored as NULL, empty messages as empty bytes
+type Serializer struct{}
+
+// Scan implements serializer interface
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) (err error) {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var bytes []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			bytes = v
+		case string:
+			bytes = []byte(v)
+		default:
+			return fmt.Errorf("failed to unmarshal protobuf value: %#v", dbValue)
+		}
+
+		if field.FieldType.Kind() != reflect.Ptr {
+			return fmt.Errorf("invalid field type %s for Serializer, only pointers of proto.Message supported", field.FieldType)
+		}
+
+		message, ok := reflect.New(field.FieldType.Elem()).Interface().(proto.Message)
+		if !ok {
+			return fmt.Errorf("invalid field type %s for Serializer, only pointers of proto.Message supported", field.FieldType)
+		}
+
+		if err = proto.Unmarshal(bytes, message); err != nil {
+			return err
+		}
+		fieldValue.Elem().Set(reflect.ValueOf(message))
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return
+}
+
+// Value implements serializer interface
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	if rv := reflect.ValueOf(fieldValue); !rv.IsValid() || rv.Kind() == reflect.Ptr && rv.IsNil() {
+		if field.TagSettings["NOT NULL"] != "" {
+			return []byte{}, nil
+		}
+		return nil, nil
+	}
+
+	message, ok := fieldValue.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("invalid field type %#v for Serializer, only pointers of proto.Message supported", fieldValue)
+	}
+
+	result, err := proto.Marshal(message)
+	if result == nil && err == nil {
+		// empty messages are marshaled to nil, which would be stored as NULL
+		result = []byte{}
+	}
+	return result, err
+}
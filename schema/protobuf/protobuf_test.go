@@ -0,0 +1,79 @@
+package protobuf
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"gorm.io/gorm/schema"
+)
+
+type message struct {
+	ID       uint
+	Detail   *wrapperspb.StringValue `gorm:"serializer:protobuf"`
+	Required *wrapperspb.StringValue `gorm:"serializer:protobuf;not null"`
+}
+
+func TestSerializer(t *testing.T) {
+	sch, err := schema.Parse(&message{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("failed to parse schema, got error %v", err)
+	}
+
+	if _, ok := schema.GetSerializer("protobuf"); !ok {
+		t.Fatalf("serializer protobuf should be registered")
+	}
+
+	ctx := context.Background()
+	detail, required := sch.LookUpField("Detail"), sch.LookUpField("Required")
+
+	tests := []struct {
+		name    string
+		field   *schema.Field
+		value   *wrapperspb.StringValue
+		want    interface{}
+		scanned *wrapperspb.StringValue
+	}{
+		{name: "message", field: detail, value: wrapperspb.String("detail"), want: mustMarshal(t, wrapperspb.String("detail")), scanned: wrapperspb.String("detail")},
+		{name: "empty", field: detail, value: &wrapperspb.StringValue{}, want: []byte{}, scanned: &wrapperspb.StringValue{}},
+		{name: "nil", field: detail, value: nil, want: nil, scanned: nil},
+		{name: "nil not null", field: required, value: nil, want: []byte{}, scanned: &wrapperspb.StringValue{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Serializer{}.Value(ctx, tt.field, reflect.ValueOf(&message{}), tt.value)
+			if err != nil {
+				t.Fatalf("Value() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Value() got = %#v, want %#v", got, tt.want)
+			}
+
+			var scanned message
+			if err := (Serializer{}).Scan(ctx, tt.field, reflect.ValueOf(&scanned), got); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			value := tt.field.ReflectValueOf(ctx, reflect.ValueOf(&scanned)).Interface().(*wrapperspb.StringValue)
+			if (value == nil) != (tt.scanned == nil) || !proto.Equal(value, tt.scanned) {
+				t.Errorf("Scan() got = %v, want %v", value, tt.scanned)
+			}
+		})
+	}
+
+	if err := (Serializer{}).Scan(ctx, detail, reflect.ValueOf(&message{}), 1); err == nil {
+		t.Errorf("Scan() should return error for invalid values")
+	}
+}
+
+func mustMarshal(t *testing.T, m proto.Message) []byte {
+	result, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal, got error %v", err)
+	}
+	return result
+}
@@ -18,47 +18,50 @@ import (
 type callbackType string
 
 const (
-	callbackTypeBeforeCreate callbackType = "BeforeCreate"
-	callbackTypeBeforeUpdate callbackType = "BeforeUpdate"
-	callbackTypeAfterCreate  callbackType = "AfterCreate"
-	callbackTypeAfterUpdate  callbackType = "AfterUpdate"
-	callbackTypeBeforeSave   callbackType = "BeforeSave"
-	callbackTypeAfterSave    callbackType = "AfterSave"
-	callbackTypeBeforeDelete callbackType = "BeforeDelete"
-	callbackTypeAfterDelete  callbackType = "AfterDelete"
-	callbackTypeAfterFind    callbackType = "AfterFind"
+	callbackTypeBeforeCreate  callbackType = "BeforeCreate"
+	callbackTypeBeforeUpdate  callbackType = "BeforeUpdate"
+	callbackTypeAfterCreate   callbackType = "AfterCreate"
+	callbackTypeAfterUpdate   callbackType = "AfterUpdate"
+	callbackTypeBeforeSave    callbackType = "BeforeSave"
+	callbackTypeAfterSave     callbackType = "AfterSave"
+	callbackTypeBeforeDelete  callbackType = "BeforeDelete"
+	callbackTypeAfterDelete   callbackType = "AfterDelete"
+	callbackTypeAfterFind     callbackType = "AfterFind"
+	callbackTypeAfterCommit   callbackType = "AfterCommit"
+	callbackTypeAfterRollback callbackType = "AfterRollback"
 )
 
 // ErrUnsupportedDataType unsupported data type
 var ErrUnsupportedDataType = errors.New("unsupported data type")
 
 type Schema struct {
-	Name                      string
-	ModelType                 reflect.Type
-	Table                     string
-	PrioritizedPrimaryField   *Field
-	DBNames                   []string
-	PrimaryFields             []*Field
-	PrimaryFieldDBNames       []string
-	Fields                    []*Field
-	FieldsByName              map[string]*Field
-	FieldsByBindName          map[string]*Field // embedded fields is 'Embed.Field'
-	FieldsByDBName            map[string]*Field
-	FieldsWithDefaultDBValue  []*Field // fields with default value assigned by database
-	Relationships             Relationships
-	CreateClauses             []clause.Interface
-	QueryClauses              []clause.Interface
-	UpdateClauses             []clause.Interface
-	DeleteClauses             []clause.Interface
-	BeforeCreate, AfterCreate bool
-	BeforeUpdate, AfterUpdate bool
-	BeforeDelete, AfterDelete bool
-	BeforeSave, AfterSave     bool
-	AfterFind                 bool
-	err                       error
-	initialized               chan struct{}
-	namer                     Namer
-	cacheStore                *sync.Map
+	Name                       string
+	ModelType                  reflect.Type
+	Table                      string
+	PrioritizedPrimaryField    *Field
+	DBNames                    []string
+	PrimaryFields              []*Field
+	PrimaryFieldDBNames        []string
+	Fields                     []*Field
+	FieldsByName               map[string]*Field
+	FieldsByBindName           map[string]*Field // embedded fields is 'Embed.Field'
+	FieldsByDBName             map[string]*Field
+	FieldsWithDefaultDBValue   []*Field // fields with default value assigned by database
+	Relationships              Relationships
+	CreateClauses              []clause.Interface
+	QueryClauses               []clause.Interface
+	UpdateClauses              []clause.Interface
+	DeleteClauses              []clause.Interface
+	BeforeCreate, AfterCreate  bool
+	BeforeUpdate, AfterUpdate  bool
+	BeforeDelete, AfterDelete  bool
+	BeforeSave, AfterSave      bool
+	AfterFind                  bool
+	AfterCommit, AfterRollback bool
+	err                        error
+	initialized                chan struct{}
+	namer                      Namer
+	cacheStore                 *sync.Map
 }
 
 func (schema *Schema) String() string {
@@ -128,6 +131,7 @@ var callbackTypes = []callbackType{
 	callbackTypeBeforeSave, callbackTypeAfterSave,
 	callbackTypeBeforeDelete, callbackTypeAfterDelete,
 	callbackTypeAfterFind,
+	callbackTypeAfterCommit, callbackTypeAfterRollback,
 }
 
 // Parse get data type from dialector
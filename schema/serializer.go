@@ -2,12 +2,15 @@ package schema
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/csv"
 	"encoding/gob"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math"
 	"reflect"
 	"strings"
@@ -35,6 +38,8 @@ func init() {
 	RegisterSerializer("json", JSONSerializer{})
 	RegisterSerializer("unixtime", UnixSecondSerializer{})
 	RegisterSerializer("gob", GobSerializer{})
+	RegisterSerializer("gzipjson", CompressedJSONSerializer{})
+	RegisterSerializer("csv", CSVSerializer{})
 }
 
 // Serializer field value serializer
@@ -187,3 +192,208 @@ func (GobSerializer) Value(ctx context.Context, field *Field, dst reflect.Value,
 	err := gob.NewEncoder(buf).Encode(fieldValue)
 	return buf.Bytes(), err
 }
+
+// Compressor compressor of CompressedJSONSerializer
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// GzipCompressor gzip compressor, zero Level uses gzip.DefaultCompression
+type GzipCompressor struct {
+	Level int
+}
+
+// Compress implements compressor interface
+func (c GzipCompressor) Compress(data []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	buf := new(bytes.Buffer)
+	writer, err := gzip.NewWriterLevel(buf, level)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err = writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress implements compressor interface
+func (GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// CompressedJSONSerializer compressed json serializer, values are compressed with Compressor, GzipCompressor by
+// default, it's registered as `gzipjson`, fields of it should use binary types, other compressors like zstd could be
+// registered with their own names
+//
+//	type Document struct {
+//	  Content map[string]interface{} `gorm:"type:bytes;serializer:gzipjson"`
+//	}
+//
+//	schema.RegisterSerializer("zstdjson", schema.CompressedJSONSerializer{Compressor: zstdCompressor})
+type CompressedJSONSerializer struct {
+	Compressor Compressor
+}
+
+func (s CompressedJSONSerializer) compressor() Compressor {
+	if s.Compressor == nil {
+		return GzipCompressor{}
+	}
+	return s.Compressor
+}
+
+// Scan implements serializer interface
+func (s CompressedJSONSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) (err error) {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var bytes []byte
+		switch v := dbValue.(type) {
+		case []byte:
+			bytes = v
+		case string:
+			bytes = []byte(v)
+		default:
+			return fmt.Errorf("failed to unmarshal compressed json value: %#v", dbValue)
+		}
+
+		if len(bytes) > 0 {
+			if bytes, err = s.compressor().Decompress(bytes); err != nil {
+				return err
+			}
+			err = json.Unmarshal(bytes, fieldValue.Interface())
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return
+}
+
+// Value implements serializer interface
+func (s CompressedJSONSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	result, err := json.Marshal(fieldValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(result) == "null" {
+		if field.TagSettings["NOT NULL"] != "" {
+			return []byte{}, nil
+		}
+		return nil, nil
+	}
+	return s.compressor().Compress(result)
+}
+
+// CSVSerializer csv serializer of string slices, values are stored as a csv record separated by Comma, `,` by
+// default, elements containing it are quoted, it's registered as `csv`
+//
+//	type Post struct {
+//	  Tags []string `gorm:"serializer:csv"`
+//	}
+type CSVSerializer struct {
+	Comma rune
+}
+
+func (s CSVSerializer) comma() rune {
+	if s.Comma == 0 {
+		return ','
+	}
+	return s.Comma
+}
+
+// Scan implements serializer interface
+func (s CSVSerializer) Scan(ctx context.Context, field *Field, dst reflect.Value, dbValue interface{}) (err error) {
+	fieldValue := reflect.New(field.FieldType)
+
+	if dbValue != nil {
+		var str string
+		switch v := dbValue.(type) {
+		case []byte:
+			str = string(v)
+		case string:
+			str = v
+		default:
+			return fmt.Errorf("failed to unmarshal csv value: %#v", dbValue)
+		}
+
+		rv := fieldValue.Elem()
+		for rv.Kind() == reflect.Ptr {
+			rv.Set(reflect.New(rv.Type().Elem()))
+			rv = rv.Elem()
+		}
+
+		if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("invalid field type %s for CSVSerializer, only []string supported", field.FieldType)
+		}
+
+		var record []string
+		if str != "" {
+			reader := csv.NewReader(strings.NewReader(str))
+			reader.Comma = s.comma()
+			if record, err = reader.Read(); err != nil {
+				return err
+			}
+		}
+
+		values := reflect.MakeSlice(rv.Type(), len(record), len(record))
+		for idx, value := range record {
+			values.Index(idx).SetString(value)
+		}
+		rv.Set(values)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue.Elem())
+	return
+}
+
+// Value implements serializer interface
+func (s CSVSerializer) Value(ctx context.Context, field *Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(fieldValue)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	switch {
+	case !rv.IsValid() || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Slice) && rv.IsNil():
+		if field.TagSettings["NOT NULL"] != "" {
+			return "", nil
+		}
+		return nil, nil
+	case rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() != reflect.String:
+		return nil, fmt.Errorf("invalid field type %#v for CSVSerializer, only []string supported", fieldValue)
+	case rv.Len() == 0:
+		return "", nil
+	case rv.Len() == 1 && rv.Index(0).String() == "":
+		// distinguish a slice of an empty string from empty slices
+		return `""`, nil
+	}
+
+	record := make([]string, rv.Len())
+	for idx := range record {
+		record[idx] = rv.Index(idx).String()
+	}
+
+	buf := new(strings.Builder)
+	writer := csv.NewWriter(buf)
+	writer.Comma = s.comma()
+	if err := writer.Write(record); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+	return strings.TrimSuffix(buf.String(), "\n"), writer.Error()
+}
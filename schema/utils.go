@@ -145,10 +145,14 @@ func GetIdentityFieldValuesMap(ctx context.Context, reflectValue reflect.Value,
 		dataResults[utils.ToStringKey(results[0]...)] = []reflect.Value{reflectValue}
 	case reflect.Slice, reflect.Array:
 		for i := 0; i < reflectValue.Len(); i++ {
-			elem := reflectValue.Index(i)
-			elemKey := elem.Interface()
+			var (
+				elem    = reflectValue.Index(i)
+				elemKey interface{}
+			)
 			if elem.Kind() != reflect.Ptr && elem.CanAddr() {
 				elemKey = elem.Addr().Interface()
+			} else {
+				elemKey = elem.Interface()
 			}
 
 			if _, ok := loaded[elemKey]; ok {
@@ -35,6 +35,7 @@ type Statement struct {
 	ColumnMapping        map[string]string // map columns
 	Joins                []join
 	Preloads             map[string][]interface{}
+	PreloadCounts        map[string][]interface{}
 	Settings             sync.Map
 	ConnPool             ConnPool
 	Schema               *schema.Schema
@@ -562,6 +563,13 @@ func (stmt *Statement) clone() *Statement {
 		newStmt.Preloads[k] = p
 	}
 
+	if len(stmt.PreloadCounts) > 0 {
+		newStmt.PreloadCounts = make(map[string][]interface{}, len(stmt.PreloadCounts))
+		for k, p := range stmt.PreloadCounts {
+			newStmt.PreloadCounts[k] = p
+		}
+	}
+
 	if len(stmt.Joins) > 0 {
 		newStmt.Joins = make([]join, len(stmt.Joins))
 		copy(newStmt.Joins, stmt.Joins)
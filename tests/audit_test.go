@@ -0,0 +1,180 @@
+package tests_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/audit"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type AuditedProduct struct {
+	ID    uint
+	Code  string
+	Price int
+}
+
+type memorySink struct {
+	logs []audit.Log
+}
+
+func (sink *memorySink) Write(db *gorm.DB, logs []audit.Log) error {
+	sink.logs = append(sink.logs, logs...)
+	return nil
+}
+
+func openAuditDB(t *testing.T, plugin *audit.Audit) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "audit.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err = db.Use(plugin); err != nil {
+		t.Fatalf("failed to use audit, got error %v", err)
+	}
+
+	if err = db.AutoMigrate(&AuditedProduct{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+	return db
+}
+
+func assertAuditLog(t *testing.T, log audit.Log, action, primaryKey string, changes ...audit.Change) {
+	t.Helper()
+	if log.Table != "audited_products" || log.Action != action || log.PrimaryKey != primaryKey || log.CreatedAt.IsZero() {
+		t.Errorf("invalid audit log, got %+v", log)
+	}
+
+	if len(log.Changes) != len(changes) {
+		t.Fatalf("expects changes %+v, got %+v", changes, log.Changes)
+	}
+
+	for idx, change := range changes {
+		AssertEqual(t, log.Changes[idx], change)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	sink := &memorySink{}
+	db := openAuditDB(t, &audit.Audit{Sink: sink})
+	db = db.WithContext(audit.WithActor(context.Background(), "admin"))
+
+	product := AuditedProduct{Code: "D42", Price: 100}
+	db.Create(&product)
+	if len(sink.logs) != 1 || sink.logs[0].Actor != "admin" {
+		t.Fatalf("failed to audit creating, got %+v", sink.logs)
+	}
+	assertAuditLog(t, sink.logs[0], audit.ActionCreate, "1", audit.Change{Column: "code", New: "D42"}, audit.Change{Column: "id", New: uint(1)}, audit.Change{Column: "price", New: 100})
+
+	db.Model(&product).Updates(map[string]interface{}{"code": "D42", "price": 200})
+	assertAuditLog(t, sink.logs[1], audit.ActionUpdate, "1", audit.Change{Column: "price", Old: 100, New: 200})
+
+	db.Model(&product).UpdateColumn("code", "F42")
+	assertAuditLog(t, sink.logs[2], audit.ActionUpdate, "1", audit.Change{Column: "code", Old: "D42", New: "F42"})
+
+	db.Model(&AuditedProduct{}).Where("price > ?", 100).Update("price", 300)
+	assertAuditLog(t, sink.logs[3], audit.ActionUpdate, "", audit.Change{Column: "price", New: 300})
+
+	db.Model(&AuditedProduct{}).Where("price > ?", 1000).Update("price", 400)
+	if len(sink.logs) != 4 {
+		t.Errorf("statements not changing rows should not be audited, got %+v", sink.logs[4:])
+	}
+
+	db.Delete(&product)
+	assertAuditLog(t, sink.logs[4], audit.ActionDelete, "1", audit.Change{Column: "code", Old: "F42"}, audit.Change{Column: "id", Old: uint(1)}, audit.Change{Column: "price", Old: 200})
+}
+
+func TestAuditPreImage(t *testing.T) {
+	db := openAuditDB(t, &audit.Audit{PreImage: true, Tables: []string{"audited_products"}})
+	if err := db.Table("audit_logs").AutoMigrate(&audit.Log{}); err != nil {
+		t.Fatalf("failed to migrate audit logs, got error %v", err)
+	}
+
+	products := []AuditedProduct{{Code: "A1", Price: 10}, {Code: "A2", Price: 20}, {Code: "A3", Price: 30}}
+	db.Create(&products)
+
+	if err := db.Model(&AuditedProduct{}).Where("price >= ?", 20).UpdateColumn("price", gorm.Expr("price * 2")).Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	if err := db.Where("code = ?", "A1").Delete(&AuditedProduct{}).Error; err != nil {
+		t.Fatalf("failed to delete, got error %v", err)
+	}
+
+	var logs []audit.Log
+	if err := db.Table("audit_logs").Order("id").Find(&logs).Error; err != nil || len(logs) != 6 {
+		t.Fatalf("failed to find audit logs, got %+v, error %v", logs, err)
+	}
+
+	for idx, log := range logs[:3] {
+		if log.Action != audit.ActionCreate || len(log.Changes) != 3 {
+			t.Errorf("invalid audit log %v of creating, got %+v", idx, log)
+		}
+	}
+
+	assertAuditLog(t, logs[3], audit.ActionUpdate, "2", audit.Change{Column: "price", Old: float64(20), New: float64(40)})
+	assertAuditLog(t, logs[4], audit.ActionUpdate, "3", audit.Change{Column: "price", Old: float64(30), New: float64(60)})
+	assertAuditLog(t, logs[5], audit.ActionDelete, "1", audit.Change{Column: "code", Old: "A1"}, audit.Change{Column: "id", Old: float64(1)}, audit.Change{Column: "price", Old: float64(10)})
+}
+
+func TestAuditPointerTableSink(t *testing.T) {
+	db := openAuditDB(t, &audit.Audit{Sink: &audit.TableSink{Table: "product_audit_logs"}})
+	if err := db.Table("product_audit_logs").AutoMigrate(&audit.Log{}); err != nil {
+		t.Fatalf("failed to migrate audit logs, got error %v", err)
+	}
+
+	product := AuditedProduct{Code: "P1", Price: 10}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := db.Model(&product).Update("price", 20).Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	var logs []audit.Log
+	if err := db.Table("product_audit_logs").Order("id").Find(&logs).Error; err != nil || len(logs) != 2 {
+		t.Fatalf("failed to find audit logs, got %+v, error %v", logs, err)
+	}
+	assertAuditLog(t, logs[1], audit.ActionUpdate, "1", audit.Change{Column: "price", Old: float64(10), New: float64(20)})
+}
+
+func TestAuditPreImageJoins(t *testing.T) {
+	sink := &memorySink{}
+	db := openAuditDB(t, &audit.Audit{Sink: sink, PreImage: true, Tables: []string{"users"}})
+	if err := db.AutoMigrate(&Company{}, &User{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	companies := []Company{{Name: "audit_joins_company"}, {Name: "audit_joins_other"}}
+	db.Create(&companies)
+	users := []User{
+		{Name: "audit_joins_1", Age: 10, CompanyID: &companies[0].ID},
+		{Name: "audit_joins_2", Age: 20, CompanyID: &companies[1].ID},
+	}
+	db.Create(&users)
+	sink.logs = nil
+
+	result := db.Model(&User{}).InnerJoins("Company").Where("Company.name = ?", "audit_joins_company").UpdateColumn("age", 30)
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("failed to update with joins, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if len(sink.logs) != 1 || sink.logs[0].Action != audit.ActionUpdate || sink.logs[0].PrimaryKey != fmt.Sprint(users[0].ID) {
+		t.Fatalf("joined updates should be audited with the joined rows, got %+v", sink.logs)
+	}
+	AssertEqual(t, sink.logs[0].Changes, []audit.Change{{Column: "age", Old: int64(10), New: int64(30)}})
+
+	result = db.Unscoped().InnerJoins("Company").Where("Company.name = ?", "audit_joins_other").Delete(&User{})
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("failed to delete with joins, got error %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if len(sink.logs) != 2 || sink.logs[1].Action != audit.ActionDelete || sink.logs[1].PrimaryKey != fmt.Sprint(users[1].ID) {
+		t.Fatalf("joined deletes should be audited with the joined rows, got %+v", sink.logs)
+	}
+}
@@ -0,0 +1,99 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestCaseExpression(t *testing.T) {
+	users := []User{
+		*GetUser("case_1", Config{}),
+		*GetUser("case_2", Config{}),
+		*GetUser("case_3", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age = 10, 30, 70
+	DB.Create(&users)
+
+	ageGroup := clause.Case{
+		Whens: []clause.When{
+			{Cond: clause.Lt{Column: "age", Value: 18}, Then: "minor"},
+			{Cond: clause.Lt{Column: "age", Value: 60}, Then: "adult"},
+		},
+		Else: "senior",
+	}
+
+	type ageGroupResult struct {
+		Name     string
+		AgeGroup string
+	}
+
+	var results []ageGroupResult
+	if err := DB.Model(&User{}).Select("name, ? AS age_group", ageGroup).Where("name LIKE ?", "case_%").
+		Order("name").Scan(&results).Error; err != nil {
+		t.Fatalf("failed to select case expression, got error %v", err)
+	}
+
+	if len(results) != 3 || results[0].AgeGroup != "minor" || results[1].AgeGroup != "adult" || results[2].AgeGroup != "senior" {
+		t.Errorf("failed to select case expression, got %+v", results)
+	}
+
+	var names []string
+	if err := DB.Model(&User{}).Where("name LIKE ?", "case_%").Order(clause.Case{
+		Value: clause.Column{Name: "name"},
+		Whens: []clause.When{{Cond: "case_2", Then: 1}, {Cond: "case_3", Then: 2}},
+		Else:  3,
+	}).Pluck("name", &names).Error; err != nil {
+		t.Fatalf("failed to order by case expression, got error %v", err)
+	}
+
+	if len(names) != 3 || names[0] != "case_2" || names[1] != "case_3" || names[2] != "case_1" {
+		t.Errorf("failed to order by case expression, got %v", names)
+	}
+
+	result := DB.Model(&User{}).Where("name LIKE ?", "case_%").Updates(map[string]interface{}{
+		"age": clause.Case{
+			Value: clause.Column{Name: "id"},
+			Whens: []clause.When{{Cond: users[0].ID, Then: 11}, {Cond: users[1].ID, Then: 31}},
+			Else:  clause.Column{Name: "age"},
+		},
+	})
+	if result.Error != nil || result.RowsAffected != 3 {
+		t.Fatalf("failed to bulk update with case expression, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	var updated []User
+	DB.Where("name LIKE ?", "case_%").Order("name").Find(&updated)
+	if len(updated) != 3 || updated[0].Age != 11 || updated[1].Age != 31 || updated[2].Age != 70 {
+		t.Errorf("failed to bulk update with case expression, got %+v", updated)
+	}
+
+	if err := DB.Model(&users[2]).Update("age", clause.Case{
+		Whens: []clause.When{{Cond: clause.Gt{Column: "age", Value: 60}, Then: clause.Expr{SQL: "age - ?", Vars: []interface{}{10}}}},
+		Else:  clause.Column{Name: "age"},
+	}).Error; err != nil {
+		t.Fatalf("failed to update with case expression, got error %v", err)
+	}
+
+	var user User
+	DB.First(&user, users[2].ID)
+	if user.Age != 60 {
+		t.Errorf("failed to update with case expression, got age %v", user.Age)
+	}
+
+	if _, err := gorm.G[User](DB).Where("id = ?", users[0].ID).Set(clause.Assignment{
+		Column: clause.Column{Name: "name"},
+		Value:  clause.Case{Whens: []clause.When{{Cond: clause.Eq{Column: "age", Value: 11}, Then: "case_1_updated"}}, Else: clause.Column{Name: "name"}},
+	}).Update(context.Background()); err != nil {
+		t.Fatalf("failed to update with case expression through generics, got error %v", err)
+	}
+
+	var user1 User
+	DB.First(&user1, users[0].ID)
+	if user1.Name != "case_1_updated" {
+		t.Errorf("failed to update with case expression through generics, got name %v", user1.Name)
+	}
+}
@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -808,3 +809,78 @@ func TestCreateFromMapWithTable(t *testing.T) {
 		t.Errorf("failed to create data from map with table, @id != id")
 	}
 }
+
+func TestCreateFrom(t *testing.T) {
+	type UserArchive struct {
+		ID     uint
+		UserID uint `gorm:"uniqueIndex"`
+		Name   string
+		Age    uint
+	}
+
+	DB.Migrator().DropTable(&UserArchive{})
+	if err := DB.AutoMigrate(&UserArchive{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	users := []User{*GetUser("create_from_1", Config{}), *GetUser("create_from_2", Config{}), *GetUser("create_from_3", Config{})}
+	users[0].Age, users[1].Age, users[2].Age = 10, 20, 30
+	DB.Create(&users)
+
+	query := DB.Model(&User{}).Select("id", "name", "age").Where("name IN ?", []string{users[0].Name, users[1].Name})
+	result := DB.Model(&UserArchive{}).CreateFrom(query, "user_id", "name", "age")
+	if result.Error != nil || result.RowsAffected != 2 {
+		t.Fatalf("failed to create from query, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	var archives []UserArchive
+	DB.Order("user_id").Find(&archives)
+	if len(archives) != 2 || archives[0].UserID != users[0].ID || archives[0].Name != users[0].Name || archives[1].Age != 20 {
+		t.Errorf("failed to create from query, got %+v", archives)
+	}
+
+	sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&UserArchive{}).CreateFrom(DB.Model(&User{}).Select("id", "name").Where("age > ?", 60), "user_id", "name")
+	})
+	assertEqualSQL(t, `INSERT INTO "user_archives" ("user_id","name") SELECT "id","name" FROM "users" WHERE age > 60 AND "users"."deleted_at" IS NULL`, sql)
+
+	if DB.Dialector.Name() != "sqlserver" {
+		result = DB.Model(&UserArchive{}).Clauses(clause.OnConflict{DoNothing: true}).CreateFrom(query, "user_id", "name", "age")
+		if result.Error != nil || result.RowsAffected != 0 {
+			t.Errorf("failed to create from query on conflict, got rows affected %v, error %v", result.RowsAffected, result.Error)
+		}
+	}
+
+	if DB.Dialector.Name() == "sqlite" || DB.Dialector.Name() == "postgres" || DB.Dialector.Name() == "gaussdb" {
+		var returned []UserArchive
+		if err := DB.Model(&returned).Clauses(clause.Returning{}).
+			CreateFrom(DB.Model(&User{}).Select("id", "name").Where("id = ?", users[2].ID), "user_id", "name").Error; err != nil {
+			t.Fatalf("failed to create from query with returning, got error %v", err)
+		}
+
+		if len(returned) != 1 || returned[0].ID == 0 || returned[0].UserID != users[2].ID || returned[0].Name != users[2].Name {
+			t.Errorf("failed to create from query with returning, got %+v", returned)
+		}
+	}
+
+	if err := DB.Model(&UserArchive{}).CreateFrom(nil).Error; !errors.Is(err, gorm.ErrSubQueryRequired) {
+		t.Errorf("should returns ErrSubQueryRequired when creating from nil query, got %v", err)
+	}
+}
+
+func TestGenericsCreateFrom(t *testing.T) {
+	ctx := context.Background()
+	users := []User{*GetUser("generics_create_from_1", Config{}), *GetUser("generics_create_from_2", Config{})}
+	DB.Create(&users)
+
+	count, err := gorm.G[User](DB).Table("users").CreateFrom(ctx,
+		gorm.G[User](DB).Select("name", "age").Where("name LIKE ?", "generics_create_from_%"), "name", "age")
+	if err != nil || count != 2 {
+		t.Fatalf("failed to create from query through generics, got rows affected %v, error %v", count, err)
+	}
+
+	copied, err := gorm.G[User](DB).Where("name LIKE ?", "generics_create_from_%").Find(ctx)
+	if err != nil || len(copied) != 4 {
+		t.Errorf("failed to create from query through generics, got %+v, error %v", copied, err)
+	}
+}
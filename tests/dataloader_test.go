@@ -0,0 +1,122 @@
+package tests_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/dataloader"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestDataLoader(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dataloader.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err = db.AutoMigrate(&User{}, &Account{}, &Pet{}, &Company{}, &Toy{}, &Language{}, &Tools{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	var queries int32
+	db.Callback().Query().After("gorm:query").Register("count_queries", func(*gorm.DB) {
+		atomic.AddInt32(&queries, 1)
+	})
+
+	db.Create([]*User{
+		GetUser("dataloader-1", Config{Pets: 2, Toys: 1, Company: true}),
+		GetUser("dataloader-2", Config{Pets: 1, Company: true}),
+		GetUser("dataloader-3", Config{Pets: 3, Company: true}),
+	})
+
+	var users []*User
+	db.Order("id").Find(&users)
+
+	loadAll := func(loader *dataloader.Loader, name string, conds ...interface{}) {
+		var wg sync.WaitGroup
+		for _, user := range users {
+			wg.Add(1)
+			go func(user *User) {
+				defer wg.Done()
+				if err := loader.Lazy(user, name, conds...).Load(context.Background()); err != nil {
+					t.Errorf("failed to load %v, got error %v", name, err)
+				}
+			}(user)
+		}
+		wg.Wait()
+	}
+
+	// has many, nested relationships are loaded with their own queries
+	atomic.StoreInt32(&queries, 0)
+	loadAll(&dataloader.Loader{DB: db, Wait: 50 * time.Millisecond}, "Pets.Toy")
+	AssertEqual(t, atomic.LoadInt32(&queries), int32(2))
+	for idx, count := range []int{2, 1, 3} {
+		AssertEqual(t, len(users[idx].Pets), count)
+		for _, pet := range users[idx].Pets {
+			AssertEqual(t, *pet.UserID, users[idx].ID)
+		}
+	}
+
+	// belongs to with conditions
+	atomic.StoreInt32(&queries, 0)
+	loadAll(&dataloader.Loader{DB: db, Wait: 50 * time.Millisecond}, "Company", "name <> ?", "")
+	AssertEqual(t, atomic.LoadInt32(&queries), int32(1))
+	for _, user := range users {
+		if user.Company.ID == 0 || user.Company.ID != *user.CompanyID {
+			t.Errorf("invalid company %+v of user %v", user.Company, user.Name)
+		}
+	}
+
+	// full batches are loaded at once
+	atomic.StoreInt32(&queries, 0)
+	loadAll(&dataloader.Loader{DB: db, Wait: 50 * time.Millisecond, MaxBatch: 2}, "Toys")
+	AssertEqual(t, atomic.LoadInt32(&queries), int32(2))
+	AssertEqual(t, len(users[0].Toys), 1)
+
+	// canceled loads don't fail other loads of the batch, and their records are removed from the batch
+	var canceled, other User
+	db.First(&canceled, users[0].ID)
+	db.First(&other, users[1].ID)
+
+	loader := &dataloader.Loader{DB: db, Wait: 50 * time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() { errs <- loader.Lazy(&canceled, "Pets").Load(ctx) }()
+	time.Sleep(10 * time.Millisecond)
+	go func() { errs <- loader.Lazy(&other, "Pets").Load(context.Background()) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil && err != context.Canceled {
+			t.Errorf("failed to load pets, got error %v", err)
+		}
+	}
+	AssertEqual(t, len(other.Pets), 1)
+	AssertEqual(t, len(canceled.Pets), 0)
+
+	// loaded relationships are not loaded again
+	loader = dataloader.New(db)
+	rel := loader.Lazy(users[0], "Account")
+	if err := rel.Load(context.Background()); err != nil {
+		t.Fatalf("failed to load account, got error %v", err)
+	}
+
+	atomic.StoreInt32(&queries, 0)
+	rel.Load(context.Background())
+	AssertEqual(t, atomic.LoadInt32(&queries), int32(0))
+
+	if err := loader.Lazy(*users[0], "Pets").Load(context.Background()); err != gorm.ErrInvalidValue {
+		t.Errorf("should return invalid value error, got %v", err)
+	}
+
+	if err := loader.Lazy(users[0], "Unknown").Load(context.Background()); err == nil {
+		t.Errorf("should return error for unsupported relations")
+	}
+}
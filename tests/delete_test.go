@@ -256,3 +256,39 @@ func TestDeleteReturning(t *testing.T) {
 		t.Errorf("failed to delete data, current count %v", count)
 	}
 }
+
+func TestDeleteWithJoins(t *testing.T) {
+	users := []User{
+		*GetUser("delete_joins_1", Config{Company: true}),
+		*GetUser("delete_joins_2", Config{Company: true}),
+		*GetUser("delete_joins_3", Config{}),
+	}
+	users[0].Company.Name = "delete_joins_company"
+	DB.Create(&users)
+
+	result := DB.InnerJoins("Company").Where("Company.name = ?", "delete_joins_company").Delete(&User{})
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("failed to soft delete with joins, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	var count int64
+	DB.Model(&User{}).Where("name LIKE ?", "delete_joins_%").Count(&count)
+	if count != 2 {
+		t.Errorf("failed to soft delete with joins, got %v users left", count)
+	}
+
+	result = DB.Unscoped().Joins("Company").Where("Company.name LIKE ?", "company-delete_joins_%").Delete(&User{})
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("failed to delete with joins, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	var names []string
+	DB.Unscoped().Model(&User{}).Where("name LIKE ?", "delete_joins_%").Order("name").Pluck("name", &names)
+	if len(names) != 2 || names[0] != "delete_joins_1" || names[1] != "delete_joins_3" {
+		t.Errorf("failed to delete with joins, got %v", names)
+	}
+
+	if err := DB.Unscoped().Joins("Company").Delete(&User{}).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should returns ErrMissingWhereClause when deleting with joins only, got %v", err)
+	}
+}
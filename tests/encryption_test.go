@@ -0,0 +1,161 @@
+package tests_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/encryption"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type EncryptedProfile struct {
+	Phone   string
+	Address string
+}
+
+type EncryptedUser struct {
+	ID         uint
+	Name       string
+	Email      string            `gorm:"serializer:encrypted"`
+	EmailIndex string            `gorm:"blindindex:Email;index"`
+	SSN        string            `gorm:"serializer:encrypted;deterministic"`
+	Profile    *EncryptedProfile `gorm:"serializer:encrypted"`
+}
+
+func TestEncryption(t *testing.T) {
+	keyring := &encryption.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}}
+	enc := &encryption.Encryption{Keyring: keyring, IndexKey: []byte("index key")}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "encryption.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err = db.Use(enc); err != nil {
+		t.Fatalf("failed to use encryption, got error %v", err)
+	}
+
+	if err = db.AutoMigrate(&EncryptedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	users := []EncryptedUser{
+		{Name: "jinzhu", Email: "jinzhu@example.org", SSN: "123-45-6789", Profile: &EncryptedProfile{Phone: "555-0100", Address: "Shanghai"}},
+		{Name: "gorm", Email: "gorm@example.org", SSN: "123-45-6789"},
+	}
+	if err = db.Create(&users).Error; err != nil {
+		t.Fatalf("failed to create users, got error %v", err)
+	}
+
+	type rawUser struct {
+		Name       string
+		Email      string
+		EmailIndex string
+		SSN        string
+		Profile    *string
+	}
+
+	raws := func() (raws []rawUser) {
+		db.Table("encrypted_users").Order("id").Find(&raws)
+		return raws
+	}
+
+	stored := raws()
+	if !strings.HasPrefix(stored[0].Email, "k1:") || strings.Contains(stored[0].Email, "jinzhu") {
+		t.Errorf("email should be encrypted with the current key, got %v", stored[0].Email)
+	}
+	AssertEqual(t, stored[0].EmailIndex, enc.BlindIndex("jinzhu@example.org"))
+	AssertEqual(t, stored[0].SSN, stored[1].SSN)
+
+	// nonces of deterministic values are not derived with the encryption key
+	mac := hmac.New(sha256.New, keyring.Keys["k1"])
+	mac.Write([]byte("123-45-6789"))
+	if data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored[0].SSN, "k1:")); err != nil || bytes.HasPrefix(data, mac.Sum(nil)[:12]) {
+		t.Errorf("nonces of deterministic values should be derived with a separated key, got %v, error %v", stored[0].SSN, err)
+	}
+	if stored[1].Profile != nil {
+		t.Errorf("nil values should be stored as NULL, got %v", *stored[1].Profile)
+	}
+
+	var found []EncryptedUser
+	if err = db.Order("id").Find(&found).Error; err != nil {
+		t.Fatalf("failed to find users, got error %v", err)
+	}
+	AssertEqual(t, found, users)
+
+	// blind indexes
+	var user EncryptedUser
+	if err = db.Where("email_index = ?", enc.BlindIndex("gorm@example.org")).First(&user).Error; err != nil {
+		t.Fatalf("failed to find user by blind index, got error %v", err)
+	}
+	AssertEqual(t, user.Name, "gorm")
+
+	db.Model(&user).Update("email", "gorm2@example.org")
+	AssertEqual(t, user.Email, "gorm2@example.org")
+	db.Model(&users[0]).Updates(EncryptedUser{Email: "jinzhu2@example.org"})
+	for idx, email := range []string{"jinzhu2@example.org", "gorm2@example.org"} {
+		var updated EncryptedUser
+		if err = db.Where("email_index = ?", enc.BlindIndex(email)).First(&updated).Error; err != nil {
+			t.Fatalf("blind index should be updated, got error %v", err)
+		}
+		AssertEqual(t, updated.ID, users[idx].ID)
+		AssertEqual(t, updated.Email, email)
+	}
+
+	// key rotation
+	keyring.Keys["k2"] = bytes.Repeat([]byte{2}, 32)
+	keyring.CurrentID = "k2"
+	db.Create(&EncryptedUser{Name: "rotated", Email: "rotated@example.org"})
+
+	rotated, err := enc.Rotate(db, &[]EncryptedUser{}, 1)
+	if err != nil {
+		t.Fatalf("failed to rotate keys, got error %v", err)
+	}
+	AssertEqual(t, rotated, 2)
+
+	for _, raw := range raws() {
+		if !strings.HasPrefix(raw.Email, "k2:") || !strings.HasPrefix(raw.SSN, "k2:") && raw.SSN != "" {
+			t.Errorf("values should be encrypted with the rotated key, got %+v", raw)
+		}
+	}
+
+	delete(keyring.Keys, "k1")
+	if err = db.Order("id").Find(&found).Error; err != nil {
+		t.Fatalf("failed to find rotated users, got error %v", err)
+	}
+	AssertEqual(t, len(found), 3)
+	AssertEqual(t, found[0].Email, "jinzhu2@example.org")
+	AssertEqual(t, found[0].Profile, users[0].Profile)
+	AssertEqual(t, found[1].SSN, "123-45-6789")
+
+	// serializers are registered globally
+	another, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "another.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	otherKeyring := &encryption.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": bytes.Repeat([]byte{3}, 32)}}
+	if err = another.Use(&encryption.Encryption{Keyring: otherKeyring, IndexKey: []byte("index key")}); err == nil {
+		t.Errorf("should return error when using another keyring")
+	}
+
+	// blind indexes require index keys
+	if err = another.Use(&encryption.Encryption{Keyring: keyring}); err != nil {
+		t.Fatalf("failed to use encryption with the same keyring, got error %v", err)
+	}
+
+	if err = another.AutoMigrate(&EncryptedUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err = another.Create(&EncryptedUser{Name: "no index key", Email: "jinzhu@example.org"}).Error; err == nil {
+		t.Errorf("should return error when creating blind indexes without index keys")
+	}
+}
@@ -0,0 +1,96 @@
+//go:build go1.23
+
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestIter(t *testing.T) {
+	users := []User{*GetUser("iter", Config{}), *GetUser("iter", Config{}), *GetUser("iter", Config{})}
+	for idx := range users {
+		users[idx].Age = uint(idx + 10)
+	}
+	DB.Create(&users)
+
+	var (
+		user  User
+		count int
+	)
+	for idx, err := range DB.Where("name = ?", "iter").Order("id").Iter(&user) {
+		if err != nil {
+			t.Fatalf("failed to iterate, got error %v", err)
+		}
+
+		if idx != count || user.ID != users[idx].ID || user.Age != users[idx].Age {
+			t.Errorf("failed to iterate, expects %v %+v, got %v %+v", count, users[count], idx, user)
+		}
+		count++
+	}
+
+	if count != len(users) {
+		t.Errorf("failed to iterate all records, got %v", count)
+	}
+
+	for _, err := range DB.Where("name = ?", "iter").Iter(&user) {
+		if err != nil {
+			t.Fatalf("failed to iterate, got error %v", err)
+		}
+		break
+	}
+
+	if sqlDB, err := DB.DB(); err != nil || sqlDB.Stats().InUse != 0 {
+		t.Errorf("rows should be closed if iteration stops early, got error %v", err)
+	}
+
+	for _, err := range DB.Session(&gorm.Session{DryRun: true}).Model(&User{}).Iter(&user) {
+		if !errors.Is(err, gorm.ErrDryRunModeUnsupported) {
+			t.Errorf("should returns dry run mode unsupported error, got %v", err)
+		}
+	}
+
+	var results []User
+	for user, err := range gorm.G[User](DB).Where("name = ?", "iter").Order("age desc").Iter(context.Background()) {
+		if err != nil {
+			t.Fatalf("failed to iterate with generics, got error %v", err)
+		}
+		results = append(results, user)
+	}
+
+	if len(results) != 3 || results[0].ID != users[2].ID || results[2].ID != users[0].ID {
+		t.Errorf("failed to iterate with generics, got %+v", results)
+	}
+}
+
+func TestIterHooks(t *testing.T) {
+	DB.Migrator().DropTable(&Product{})
+	DB.AutoMigrate(&Product{})
+
+	DB.Create(&[]Product{{Code: "iter_hooks"}, {Code: "iter_hooks"}})
+
+	var product Product
+	for _, err := range DB.Where("code = ?", "iter_hooks").Iter(&product) {
+		if err != nil {
+			t.Fatalf("failed to iterate, got error %v", err)
+		}
+
+		if product.AfterFindCallTimes != 1 {
+			t.Errorf("AfterFind should be called for every record, got %v", product.AfterFindCallTimes)
+		}
+	}
+
+	for _, err := range DB.Session(&gorm.Session{SkipHooks: true}).Where("code = ?", "iter_hooks").Iter(&product) {
+		if err != nil {
+			t.Fatalf("failed to iterate, got error %v", err)
+		}
+
+		if product.AfterFindCallTimes != 0 {
+			t.Errorf("AfterFind should not be called if hooks are skipped, got %v", product.AfterFindCallTimes)
+		}
+	}
+}
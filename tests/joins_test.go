@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	. "gorm.io/gorm/utils/tests"
 )
 
@@ -476,3 +478,164 @@ func TestJoinsPreload_Issue7013_NoEntries(t *testing.T) {
 
 	AssertEqual(t, len(entries), 0)
 }
+
+func TestJoinsHasMany(t *testing.T) {
+	users := []User{
+		*GetUser("joins_has_many_1", Config{Pets: 3, Toys: 2}),
+		*GetUser("joins_has_many_2", Config{Pets: 1}),
+		*GetUser("joins_has_many_3", Config{}),
+	}
+	for _, pet := range users[0].Pets {
+		pet.Toy = Toy{Name: pet.Name + "_toy"}
+	}
+	DB.Create(&users)
+
+	names := []string{users[0].Name, users[1].Name, users[2].Name}
+	checkPets := func(t *testing.T, pets, expects []*Pet, withToys bool) {
+		t.Helper()
+		sort.Slice(pets, func(i, j int) bool { return pets[i].ID < pets[j].ID })
+		if len(pets) != len(expects) {
+			t.Fatalf("pets count should be %v, got %v", len(expects), len(pets))
+		}
+		for idx, pet := range pets {
+			AssertEqual(t, pet.Name, expects[idx].Name)
+			AssertEqual(t, pet.UserID, expects[idx].UserID)
+			if withToys {
+				AssertEqual(t, pet.Toy.Name, expects[idx].Toy.Name)
+			}
+		}
+	}
+
+	var found []User
+	result := DB.Joins("Pets").Joins("Toys").Order("users.id").Find(&found, "users.name IN ?", names)
+	if result.Error != nil {
+		t.Fatalf("failed to join has many relations, got error %v", result.Error)
+	}
+	AssertEqual(t, result.RowsAffected, 3)
+	AssertEqual(t, len(found), 3)
+	for idx, user := range found {
+		AssertEqual(t, user.Name, users[idx].Name)
+		AssertEqual(t, len(user.Toys), len(users[idx].Toys))
+		checkPets(t, user.Pets, users[idx].Pets, false)
+	}
+
+	// nested relations of has many relations
+	var nested []User
+	if err := DB.Joins("Pets").Joins("Pets.Toy").Order("users.id").Find(&nested, "users.name IN ?", names[:2]).Error; err != nil {
+		t.Fatalf("failed to join nested relations of has many relations, got error %v", err)
+	}
+	AssertEqual(t, len(nested), 2)
+	checkPets(t, nested[0].Pets, users[0].Pets, true)
+	checkPets(t, nested[1].Pets, users[1].Pets, false)
+
+	// joined rows scanned by ScanRows are scanned one by one
+	rows, err := DB.Model(&User{}).Joins("Pets").Where("users.name IN ?", names[:2]).Order("users.id").Rows()
+	if err != nil {
+		t.Fatalf("failed to query rows of has many relations, got error %v", err)
+	}
+	defer rows.Close()
+
+	var scanned []string
+	for rows.Next() {
+		var user User
+		if err := DB.ScanRows(rows, &user); err != nil {
+			t.Fatalf("failed to scan rows of has many relations, got error %v", err)
+		}
+		AssertEqual(t, len(user.Pets), 1)
+		scanned = append(scanned, user.Name)
+	}
+	AssertEqual(t, scanned, []string{names[0], names[0], names[0], names[1]})
+
+	// limits are applied to parents
+	var first User
+	if err := DB.Joins("Pets").First(&first, "users.name = ?", users[0].Name).Error; err != nil {
+		t.Fatalf("failed to join has many relations of the first record, got error %v", err)
+	}
+	AssertEqual(t, first.Name, users[0].Name)
+	checkPets(t, first.Pets, users[0].Pets, false)
+
+	var limited []User
+	if err := DB.Joins("Pets").Order("users.id").Limit(2).Find(&limited, "users.name IN ?", names).Error; err != nil {
+		t.Fatalf("failed to join has many relations with limit, got error %v", err)
+	}
+	AssertEqual(t, len(limited), 2)
+	checkPets(t, limited[0].Pets, users[0].Pets, false)
+	checkPets(t, limited[1].Pets, users[1].Pets, false)
+
+	// orders of joined tables with limits
+	var ordered []User
+	if err := DB.Joins("Pets").Order("users.id").Order("Pets.name desc").Limit(10).Find(&ordered, "users.name IN ?", names).Error; err != nil {
+		t.Fatalf("failed to join has many relations with orders of joined tables and limit, got error %v", err)
+	}
+	AssertEqual(t, len(ordered), 3)
+	AssertEqual(t, len(ordered[0].Pets), 3)
+	AssertEqual(t, ordered[0].Pets[0].Name, users[0].Pets[2].Name)
+
+	// conditions of joined tables with limits
+	var petFiltered []User
+	if err := DB.Joins("Pets").Where("Pets.name = ?", users[0].Pets[2].Name).Limit(10).Find(&petFiltered, "users.name IN ?", names).Error; err != nil {
+		t.Fatalf("failed to join has many relations with conditions of joined tables and limit, got error %v", err)
+	}
+	AssertEqual(t, len(petFiltered), 1)
+	checkPets(t, petFiltered[0].Pets, users[0].Pets[2:], false)
+
+	var petFilteredFirst User
+	if err := DB.Joins("Pets").Where("Pets.name = ?", users[1].Pets[0].Name).First(&petFilteredFirst, "users.name IN ?", names).Error; err != nil {
+		t.Fatalf("failed to join has many relations of the first record with conditions of joined tables, got error %v", err)
+	}
+	AssertEqual(t, petFilteredFirst.Name, users[1].Name)
+	checkPets(t, petFilteredFirst.Pets, users[1].Pets, false)
+
+	// conditions of joined has many relations
+	var filtered User
+	if err := DB.Joins("Pets", DB.Where(&Pet{Name: users[0].Pets[1].Name})).First(&filtered, "users.name = ?", users[0].Name).Error; err != nil {
+		t.Fatalf("failed to join has many relations with conditions, got error %v", err)
+	}
+	checkPets(t, filtered.Pets, users[0].Pets[1:2], false)
+}
+
+// jsonAggregator aggregates rows to JSON arrays with SQLite JSON functions
+type jsonAggregator struct {
+	gorm.Dialector
+}
+
+func (jsonAggregator) JSONArrayAgg(columns []clause.Column) clause.Expression {
+	placeholders := make([]string, 0, len(columns))
+	vars := make([]interface{}, 0, len(columns)*2)
+	for _, column := range columns {
+		placeholders = append(placeholders, "?,?")
+		vars = append(vars, column.Name, column)
+	}
+	return clause.Expr{SQL: "json_group_array(json_object(" + strings.Join(placeholders, ",") + "))", Vars: vars}
+}
+
+func TestJoinsHasManyJSONAggregation(t *testing.T) {
+	if DB.Dialector.Name() != "sqlite" {
+		t.Skip("JSON aggregation of the test dialector requires SQLite")
+	}
+
+	users := []User{
+		*GetUser("joins_json_agg_1", Config{Pets: 2, Toys: 3}),
+		*GetUser("joins_json_agg_2", Config{}),
+	}
+	DB.Create(&users)
+
+	db := DB.Session(&gorm.Session{})
+	db.Dialector = jsonAggregator{Dialector: db.Dialector}
+
+	var found []User
+	result := db.Joins("Pets").Joins("Toys").Joins("Company").Order("users.id").Limit(10).Find(&found, "users.name IN ?", []string{users[0].Name, users[1].Name})
+	if result.Error != nil {
+		t.Fatalf("failed to aggregate has many relations, got error %v", result.Error)
+	}
+
+	stmt := db.Session(&gorm.Session{DryRun: true}).Joins("Pets").Find(&[]User{}).Statement
+	if !regexp.MustCompile(`json_group_array`).MatchString(stmt.SQL.String()) {
+		t.Errorf("has many relations should be aggregated, got %v", stmt.SQL.String())
+	}
+
+	AssertEqual(t, len(found), 2)
+	CheckUser(t, found[0], users[0])
+	AssertEqual(t, len(found[1].Pets), 0)
+	AssertEqual(t, len(found[1].Toys), 0)
+}
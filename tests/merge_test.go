@@ -0,0 +1,56 @@
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestMergeToSQL(t *testing.T) {
+	db, _ := gorm.Open(DummyDialector{}, nil)
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&User{}).Merge("staging_users").As("s").On("users.id = s.id").
+			WhenMatchedDelete("s.active = ?", false).
+			WhenMatchedUpdate([]string{"Name", "age"}).
+			WhenNotMatchedInsert([]string{"id", "name", "age"}).
+			Exec()
+	})
+	assertEqualSQL(t, "MERGE INTO `users` USING `staging_users` `s` ON users.id = s.id "+
+		"WHEN MATCHED AND s.active = false THEN DELETE "+
+		"WHEN MATCHED THEN UPDATE SET `name`=`s`.`name`,`age`=`s`.`age` "+
+		"WHEN NOT MATCHED THEN INSERT (`id`,`name`,`age`) VALUES (`s`.`id`,`s`.`name`,`s`.`age`)", sql)
+
+	sql = db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&User{}).Merge(db.Table("staging_users").Where("age > ?", 18)).On("users.id = source.id").
+			WhenMatchedUpdate(map[string]interface{}{"Age": gorm.Expr("source.age")}, "users.age < source.age").
+			WhenNotMatchedInsert(map[string]interface{}{"name": "jinzhu", "age": 18}).
+			Exec()
+	})
+	assertEqualSQL(t, "MERGE INTO `users` USING (SELECT * FROM `staging_users` WHERE age > 18) AS `source` ON users.id = source.id "+
+		"WHEN MATCHED AND users.age < source.age THEN UPDATE SET `age`=source.age "+
+		"WHEN NOT MATCHED THEN INSERT (`age`,`name`) VALUES (18,\"jinzhu\")", sql)
+
+	sql = db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Table("users").Merge(clause.Values{
+			Columns: []clause.Column{{Name: "id"}, {Name: "name"}},
+			Values:  [][]interface{}{{1, "jinzhu"}},
+		}).As("v").On(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "id"}, Value: clause.Column{Table: "v", Name: "id"}}).
+			WhenMatchedUpdate(clause.Set{{Column: clause.Column{Name: "name"}, Value: clause.Column{Table: "v", Name: "name"}}}).
+			Exec()
+	})
+	assertEqualSQL(t, "MERGE INTO `users` USING (VALUES (1,\"jinzhu\")) AS `v` (`id`,`name`) ON `users`.`id` = `v`.`id` "+
+		"WHEN MATCHED THEN UPDATE SET `name`=`v`.`name`", sql)
+
+	dryRunDB := db.Session(&gorm.Session{DryRun: true})
+	if err := dryRunDB.Model(&User{}).Merge("staging_users").WhenMatchedDelete().Exec().Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should returns missing where clause error for merge without conditions, got %v", err)
+	}
+
+	if err := dryRunDB.Model(&User{}).Merge("staging_users").On("users.id = staging_users.id").Exec().Error; !errors.Is(err, gorm.ErrInvalidData) {
+		t.Errorf("should returns invalid data error for merge without actions, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/nplusone"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestNPlusOneDetector(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "nplusone.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	var reported []nplusone.Finding
+	detector := &nplusone.Detector{Report: func(ctx context.Context, finding nplusone.Finding) {
+		reported = append(reported, finding)
+	}}
+	if err = db.Use(detector); err != nil {
+		t.Fatalf("failed to use detector, got error %v", err)
+	}
+
+	if err = db.AutoMigrate(&User{}, &Account{}, &Pet{}, &Company{}, &Toy{}, &Language{}, &Tools{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	users := []*User{
+		GetUser("nplusone-1", Config{Pets: 2, Company: true}),
+		GetUser("nplusone-2", Config{Pets: 1, Company: true}),
+		GetUser("nplusone-3", Config{Pets: 1, Company: true}),
+	}
+	db.Create(&users)
+
+	// has many
+	ctx := nplusone.Track(context.Background())
+	var found []User
+	db.WithContext(ctx).Find(&found)
+	for _, user := range found {
+		db.WithContext(ctx).Where("user_id = ?", user.ID).Find(&user.Pets)
+	}
+
+	findings := nplusone.Findings(ctx)
+	if len(findings) != 1 || findings[0].Count != 3 || !strings.Contains(findings[0].Caller, "nplusone_test.go") {
+		t.Fatalf("expects a N+1 query, got %+v", findings)
+	}
+	AssertEqual(t, findings[0].SQL, "SELECT * FROM `pets` WHERE user_id = ? AND `pets`.`deleted_at` IS NULL")
+	AssertEqual(t, findings[0].Fix, "Preload(\"Pets\") of `users`")
+	AssertEqual(t, reported, findings)
+
+	// belongs to
+	ctx = nplusone.Track(context.Background())
+	db.WithContext(ctx).Find(&found)
+	for _, user := range found {
+		db.WithContext(ctx).First(&user.Company, user.CompanyID)
+	}
+
+	findings = nplusone.Findings(ctx)
+	if len(findings) != 1 {
+		t.Fatalf("expects a N+1 query, got %+v", findings)
+	}
+	AssertEqual(t, findings[0].Fix, "Joins(\"Company\") or Preload(\"Company\") of `users`")
+
+	// same parameters, preloads and untracked contexts are not reported
+	ctx = nplusone.Track(context.Background())
+	for i := 0; i < 3; i++ {
+		db.WithContext(ctx).Where("user_id = ?", found[0].ID).Find(&found[0].Pets)
+	}
+	db.WithContext(ctx).Preload("Pets").Preload("Company").Find(&found)
+	for _, user := range found {
+		db.Where("user_id = ?", user.ID).Find(&user.Pets)
+	}
+	AssertEqual(t, len(nplusone.Findings(ctx)), 0)
+
+	// strict mode fails the statement
+	detector.Strict = true
+	ctx = nplusone.Track(context.Background())
+	for idx, user := range found {
+		err := db.WithContext(ctx).Where("user_id IN ?", []uint{user.ID, 0}).Find(&user.Pets).Error
+		if (idx == 2) != errors.Is(err, nplusone.ErrNPlusOneQuery) {
+			t.Fatalf("query %v got error %v", idx, err)
+		}
+	}
+}
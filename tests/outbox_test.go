@@ -0,0 +1,147 @@
+package tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/outbox"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type OutboxOrder struct {
+	ID     uint
+	Status string
+	events []interface{}
+}
+
+func (order *OutboxOrder) Emit(event interface{}) {
+	order.events = append(order.events, event)
+}
+
+func (order *OutboxOrder) Events() []interface{} {
+	events := order.events
+	order.events = nil
+	return events
+}
+
+type OrderPlaced struct {
+	Status string
+}
+
+type OrderStatusChanged struct {
+	Status string
+}
+
+func (OrderStatusChanged) EventType() string {
+	return "order.status_changed"
+}
+
+func TestOutbox(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	box := &outbox.Outbox{}
+	if err = db.Use(box); err != nil {
+		t.Fatalf("failed to use outbox, got error %v", err)
+	}
+
+	if err = db.AutoMigrate(&OutboxOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err = db.Table("outbox_messages").AutoMigrate(&outbox.Message{}); err != nil {
+		t.Fatalf("failed to migrate outbox, got error %v", err)
+	}
+
+	messages := func() (messages []outbox.Message) {
+		db.Table("outbox_messages").Order("id").Find(&messages)
+		return messages
+	}
+
+	order := OutboxOrder{Status: "placed"}
+	order.Emit(OrderPlaced{Status: "placed"})
+	if err := db.Create(&order).Error; err != nil {
+		t.Fatalf("failed to create order, got error %v", err)
+	}
+
+	msgs := messages()
+	if len(msgs) != 1 || msgs[0].Type != "tests_test.OrderPlaced" || msgs[0].Table != "outbox_orders" || msgs[0].PrimaryKey != "1" || msgs[0].DispatchedAt != nil {
+		t.Fatalf("invalid outbox messages, got %+v", msgs)
+	}
+
+	var placed OrderPlaced
+	if err := json.Unmarshal(msgs[0].Payload, &placed); err != nil || placed.Status != "placed" {
+		t.Fatalf("invalid payload %s, got error %v", msgs[0].Payload, err)
+	}
+
+	// events are written in the transactions of statements
+	db.Transaction(func(tx *gorm.DB) error {
+		order.Emit(OrderStatusChanged{Status: "paid"})
+		tx.Model(&order).Update("status", "paid")
+		return errors.New("rollback")
+	})
+	AssertEqual(t, len(messages()), 1)
+
+	order.Emit(OrderStatusChanged{Status: "paid"})
+	db.Model(&order).Update("status", "paid")
+
+	orders := []OutboxOrder{{Status: "placed"}, {Status: "placed"}}
+	orders[0].Emit(OrderPlaced{Status: "placed"})
+	orders[1].Emit(OrderPlaced{Status: "placed"})
+	db.Create(&orders)
+
+	orders[1].Emit(OrderStatusChanged{Status: "deleted"})
+	db.Delete(&orders[1])
+
+	msgs = messages()
+	if len(msgs) != 5 {
+		t.Fatalf("expects 5 messages, got %+v", msgs)
+	}
+	AssertEqual(t, msgs[1].Type, "order.status_changed")
+	AssertEqual(t, string(msgs[1].Payload), `{"Status":"paid"}`)
+	AssertEqual(t, msgs[4].PrimaryKey, "3")
+
+	// dispatch
+	var dispatched []uint64
+	publish := func(messages []outbox.Message) error {
+		for _, message := range messages {
+			dispatched = append(dispatched, message.ID)
+		}
+		return nil
+	}
+
+	if count, err := box.Dispatch(db, 2, publish); err != nil || count != 2 {
+		t.Fatalf("failed to dispatch messages, got count %v, error %v", count, err)
+	}
+	AssertEqual(t, dispatched, []uint64{1, 2})
+
+	// messages failed to be published are claimable again
+	if count, err := box.Dispatch(db, 2, func([]outbox.Message) error { return errors.New("failed") }); err == nil || count != 0 {
+		t.Fatalf("dispatch should fail, got count %v, error %v", count, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := box.Poll(ctx, db, 10*time.Millisecond, 0, publish); !errors.Is(err, outbox.ErrInvalidLimit) {
+		t.Fatalf("poll should returns invalid limit error, got error %v", err)
+	}
+
+	if err := box.Poll(ctx, db, 10*time.Millisecond, 2, publish); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("poll should stop when the context is done, got error %v", err)
+	}
+	AssertEqual(t, dispatched, []uint64{1, 2, 3, 4, 5})
+
+	for _, message := range messages() {
+		if message.DispatchedAt == nil {
+			t.Errorf("message %v should be dispatched", message.ID)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestPaginate(t *testing.T) {
+	users := make([]User, 7)
+	for idx, age := range []uint{30, 20, 30, 10, 20, 30, 10} {
+		users[idx] = *GetUser("paginate", Config{})
+		users[idx].Age = age
+	}
+	DB.Create(&users)
+
+	// age desc, id asc
+	expects := []User{users[0], users[2], users[5], users[1], users[4], users[3], users[6]}
+	query := DB.Where("name = ?", "paginate").Order("age desc").Session(&gorm.Session{})
+
+	var (
+		pages   [][]User
+		results []User
+		cursor  string
+	)
+	for {
+		page, err := query.Paginate(&results, cursor, 3)
+		if err != nil {
+			t.Fatalf("failed to paginate, got error %v", err)
+		}
+
+		if (len(pages) == 0) != (page.Prev == "") {
+			t.Errorf("page %v should have previous cursor only if it is not the first page, got %q", len(pages), page.Prev)
+		}
+
+		pages = append(pages, results)
+		if page.Next == "" {
+			break
+		}
+		cursor = page.Next
+		results = nil
+	}
+
+	if len(pages) != 3 || len(pages[0]) != 3 || len(pages[1]) != 3 || len(pages[2]) != 1 {
+		t.Fatalf("failed to paginate, got pages %+v", pages)
+	}
+
+	for idx, user := range append(append(pages[0], pages[1]...), pages[2]...) {
+		if user.ID != expects[idx].ID {
+			t.Errorf("failed to paginate, expects %v user %v, got %v", idx, expects[idx].ID, user.ID)
+		}
+	}
+
+	// walk back from the last page
+	var prevResults []User
+	page, err := query.Paginate(&prevResults, cursor, 3)
+	if err != nil || page.Prev == "" || page.Next != "" {
+		t.Fatalf("failed to paginate, got page %+v, error %v", page, err)
+	}
+
+	prevResults = nil
+	if page, err = query.Paginate(&prevResults, page.Prev, 3); err != nil {
+		t.Fatalf("failed to paginate previous page, got error %v", err)
+	}
+
+	if len(prevResults) != 3 || prevResults[0].ID != expects[3].ID || prevResults[2].ID != expects[5].ID || page.Prev == "" || page.Next == "" {
+		t.Errorf("failed to paginate previous page, got %+v, page %+v", prevResults, page)
+	}
+
+	prevResults = nil
+	if page, err = query.Paginate(&prevResults, page.Prev, 3); err != nil {
+		t.Fatalf("failed to paginate previous page, got error %v", err)
+	}
+
+	if len(prevResults) != 3 || prevResults[0].ID != expects[0].ID || page.Prev != "" || page.Next == "" {
+		t.Errorf("failed to paginate first page backwards, got %+v, page %+v", prevResults, page)
+	}
+
+	// same direction orders are sought with row values
+	var pointers []*User
+	page, err = DB.Where("name = ?", "paginate").Order("age").Order("id").Paginate(&pointers, "", 4)
+	if err != nil || len(pointers) != 4 || pointers[0].ID != users[3].ID || pointers[1].ID != users[6].ID {
+		t.Fatalf("failed to paginate with pointers, got %+v, error %v", pointers, err)
+	}
+
+	pointers = nil
+	if _, err = DB.Where("name = ?", "paginate").Order("age").Order("id").Paginate(&pointers, page.Next, 4); err != nil || len(pointers) != 3 || pointers[2].ID != users[5].ID {
+		t.Errorf("failed to paginate with row values, got %+v, error %v", pointers, err)
+	}
+
+	if _, err = DB.Order("name").Paginate(&results, page.Next, 4); !errors.Is(err, gorm.ErrInvalidCursor) {
+		t.Errorf("should returns invalid cursor error if the order changed, got %v", err)
+	}
+
+	if _, err = DB.Paginate(&results, "invalid", 4); !errors.Is(err, gorm.ErrInvalidCursor) {
+		t.Errorf("should returns invalid cursor error, got %v", err)
+	}
+
+	generics := gorm.G[User](DB).Where("name = ?", "paginate").Order("age desc")
+	items, genericPage, err := generics.Page(context.Background(), "", 5)
+	if err != nil || len(items) != 5 || items[0].ID != expects[0].ID || genericPage.Next == "" {
+		t.Fatalf("failed to paginate with generics, got %+v, page %+v, error %v", items, genericPage, err)
+	}
+
+	items, genericPage, err = generics.Page(context.Background(), genericPage.Next, 5)
+	if err != nil || len(items) != 2 || items[1].ID != expects[6].ID || genericPage.Next != "" || genericPage.Prev == "" {
+		t.Errorf("failed to paginate next page with generics, got %+v, page %+v, error %v", items, genericPage, err)
+	}
+}
+
+func TestPaginateToSQL(t *testing.T) {
+	var users []User
+	sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		tx.Order("age desc").Paginate(&users, "", 20)
+		return tx
+	})
+	assertEqualSQL(t, "SELECT * FROM `users` WHERE `users`.`deleted_at` IS NULL ORDER BY `users`.`age` DESC,`users`.`id` LIMIT 21", sql)
+
+	sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		tx.Order("age desc").Paginate(&users, "eyJjIjpbImFnZSIsImlkIl0sInYiOlsxOCwyMF19", 20)
+		return tx
+	})
+	assertEqualSQL(t, "SELECT * FROM `users` WHERE (`users`.`age` < 18 OR (`users`.`age` = 18 AND `users`.`id` > 20)) AND `users`.`deleted_at` IS NULL ORDER BY `users`.`age` DESC,`users`.`id` LIMIT 21", sql)
+
+	sql = DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		tx.Order("age").Paginate(&users, "eyJwIjp0cnVlLCJjIjpbImFnZSIsImlkIl0sInYiOlsxOCwyMF19", 20)
+		return tx
+	})
+	assertEqualSQL(t, "SELECT * FROM `users` WHERE (`users`.`age`,`users`.`id`) < (18,20) AND `users`.`deleted_at` IS NULL ORDER BY `users`.`age` DESC,`users`.`id` DESC LIMIT 21", sql)
+}
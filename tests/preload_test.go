@@ -3,6 +3,7 @@ package tests_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"regexp"
 	"sort"
 	"strconv"
@@ -52,6 +53,122 @@ func TestPreloadWithAssociations(t *testing.T) {
 	CheckUser(t, user3, user)
 }
 
+func TestConcurrentPreload(t *testing.T) {
+	users := []User{
+		*GetUser("concurrent_preload_1", Config{Account: true, Pets: 2, Toys: 3, Company: true, Manager: true, Team: 2, Languages: 2, Friends: 1}),
+		*GetUser("concurrent_preload_2", Config{Account: true, Pets: 1, Toys: 1, Company: true, Languages: 1}),
+	}
+
+	for idx := range users {
+		for _, pet := range users[idx].Pets {
+			pet.Toy = Toy{Name: pet.Name + "_toy"}
+		}
+	}
+
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("errors happened when create: %v", err)
+	}
+
+	db := DB.Session(&gorm.Session{ConcurrentPreload: 3})
+
+	var found []User
+	if err := db.Preload(clause.Associations).Preload("Pets.Toy").Order("id").Find(&found, "name IN ?", []string{users[0].Name, users[1].Name}).Error; err != nil {
+		t.Fatalf("failed to preload concurrently, got error %v", err)
+	}
+
+	AssertEqual(t, len(found), len(users))
+	for idx, user := range found {
+		CheckUser(t, user, users[idx])
+		for i, pet := range user.Pets {
+			AssertEqual(t, pet.Toy.Name, users[idx].Pets[i].Toy.Name)
+		}
+	}
+
+	// errors of concurrent preloads are joined
+	err := db.Preload("Pets", "unknown_pet_column = ?", 1).Preload("Toys", "unknown_toy_column = ?", 1).Preload("Account").
+		Find(&found, "name IN ?", []string{users[0].Name, users[1].Name}).Error
+	if err == nil || !regexp.MustCompile("unknown_pet_column.*; .*unknown_toy_column").MatchString(err.Error()) {
+		t.Fatalf("errors of preloads should be joined, got %v", err)
+	}
+
+	errPets, errToys := errors.New("pets error"), errors.New("toys error")
+	err = db.Preload("Pets", func(tx *gorm.DB) *gorm.DB {
+		tx.AddError(errPets)
+		return tx
+	}).Preload("Toys", func(tx *gorm.DB) *gorm.DB {
+		tx.AddError(errToys)
+		return tx
+	}).Find(&found, "name IN ?", []string{users[0].Name, users[1].Name}).Error
+	if !errors.Is(err, errPets) || !errors.Is(err, errToys) {
+		t.Fatalf("all errors of preloads should be matched, got %v", err)
+	}
+
+	// preloads in transactions are sequential
+	db.Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.Preload(clause.Associations).Preload("Pets.Toy").First(&user, "name = ?", users[0].Name).Error; err != nil {
+			t.Errorf("failed to preload in transaction, got error %v", err)
+		}
+		CheckUser(t, user, users[0])
+		return nil
+	})
+}
+
+func TestPreloadCount(t *testing.T) {
+	type UserCounts struct {
+		ID            uint
+		Name          string
+		Pets          []*Pet     `gorm:"foreignKey:UserID"`
+		Toys          []Toy      `gorm:"polymorphic:Owner;polymorphicValue:users"`
+		Languages     []Language `gorm:"many2many:UserSpeak;joinForeignKey:UserID"`
+		PetCount      int64      `gorm:"count:Pets"`
+		ToyCount      int        `gorm:"count:Toys"`
+		LanguageCount int64      `gorm:"count:Languages"`
+	}
+
+	users := []User{
+		*GetUser("preload_count_1", Config{Pets: 3, Toys: 2, Languages: 2}),
+		*GetUser("preload_count_2", Config{Pets: 1}),
+		*GetUser("preload_count_3", Config{}),
+	}
+	if err := DB.Create(&users).Error; err != nil {
+		t.Fatalf("errors happened when create: %v", err)
+	}
+	DB.Delete(users[0].Pets[2])
+
+	var found []UserCounts
+	if err := DB.Table("users").PreloadCount("Pets").PreloadCount("Toys").PreloadCount("Languages").
+		Order("id").Find(&found, "name IN ?", []string{users[0].Name, users[1].Name, users[2].Name}).Error; err != nil {
+		t.Fatalf("failed to preload counts, got error %v", err)
+	}
+
+	AssertEqual(t, len(found), 3)
+	for idx, expects := range [][3]int{{2, 2, 2}, {1, 0, 0}, {0, 0, 0}} {
+		AssertEqual(t, found[idx].PetCount, expects[0])
+		AssertEqual(t, found[idx].ToyCount, expects[1])
+		AssertEqual(t, found[idx].LanguageCount, expects[2])
+		AssertEqual(t, len(found[idx].Pets), 0)
+	}
+
+	var user UserCounts
+	if err := DB.Table("users").PreloadCount("Pets", "name = ?", users[0].Pets[0].Name).
+		First(&user, "name = ?", users[0].Name).Error; err != nil {
+		t.Fatalf("failed to preload counts with conditions, got error %v", err)
+	}
+	AssertEqual(t, user.PetCount, 1)
+
+	if err := DB.Table("users").PreloadCount("Pets", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).First(&user, "name = ?", users[0].Name).Error; err != nil {
+		t.Fatalf("failed to preload counts with scopes, got error %v", err)
+	}
+	AssertEqual(t, user.PetCount, 3)
+
+	if err := DB.Table("users").PreloadCount("Friends").First(&user).Error; !errors.Is(err, gorm.ErrUnsupportedRelation) {
+		t.Errorf("should returns ErrUnsupportedRelation for unknown relations, got %v", err)
+	}
+}
+
 func TestNestedPreload(t *testing.T) {
 	user := *GetUser("nested_preload", Config{Pets: 2})
 
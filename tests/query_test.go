@@ -1047,6 +1047,13 @@ func TestOrder(t *testing.T) {
 	if !regexp.MustCompile("SELECT \\* FROM .*users.* ORDER BY FIELD\\(id,1,2,3\\)").MatchString(explainedSQL) {
 		t.Fatalf("Build Order condition, but got %v", explainedSQL)
 	}
+
+	field := clause.Expr{SQL: "FIELD(id,?)", Vars: []interface{}{[]int{1, 2, 3}}, WithoutParentheses: true}
+	stmt = dryDB.Order("age").Order(field).Order("name").Find(&User{}).Statement
+	explainedSQL = dryDB.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+	if !regexp.MustCompile("SELECT \\* FROM .*users.* ORDER BY age,FIELD\\(id,1,2,3\\),name").MatchString(explainedSQL) {
+		t.Fatalf("Build Order condition with expressions and columns, but got %v", explainedSQL)
+	}
 }
 
 func TestOrderWithAllFields(t *testing.T) {
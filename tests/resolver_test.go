@@ -0,0 +1,158 @@
+package tests_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/plugin/resolver"
+)
+
+type ResolverUser struct {
+	ID   uint
+	Name string
+}
+
+func openResolverDB(t *testing.T, file, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(file), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open %v, got error %v", file, err)
+	}
+
+	if err = db.AutoMigrate(&ResolverUser{}); err != nil {
+		t.Fatalf("failed to migrate %v, got error %v", file, err)
+	}
+
+	if name != "" {
+		db.Create(&ResolverUser{Name: name})
+	}
+	return db
+}
+
+func TestResolver(t *testing.T) {
+	dir := t.TempDir()
+	db := openResolverDB(t, filepath.Join(dir, "source.db"), "source")
+	source := db.ConnPool
+	replica1 := openResolverDB(t, filepath.Join(dir, "replica1.db"), "replica1")
+	replica2 := openResolverDB(t, filepath.Join(dir, "replica2.db"), "replica2")
+
+	if err := db.Use(&resolver.Resolver{
+		Replicas: []gorm.ConnPool{replica1.ConnPool, replica2.ConnPool},
+		Policy:   &resolver.RoundRobinPolicy{},
+	}); err != nil {
+		t.Fatalf("failed to use resolver, got error %v", err)
+	}
+
+	names := func(tx *gorm.DB) []string {
+		var result []string
+		if err := tx.Model(&ResolverUser{}).Order("id").Pluck("name", &result).Error; err != nil {
+			t.Fatalf("failed to query, got error %v", err)
+		}
+		return result
+	}
+
+	if result := names(db); len(result) != 1 || result[0] != "replica1" {
+		t.Errorf("queries should be routed to replicas in turn, got %v", result)
+	}
+
+	if result := names(db); len(result) != 1 || result[0] != "replica2" {
+		t.Errorf("queries should be routed to replicas in turn, got %v", result)
+	}
+
+	if result := names(db.Clauses(resolver.UseSource())); len(result) != 1 || result[0] != "source" {
+		t.Errorf("queries using source should be routed to source, got %v", result)
+	}
+
+	// SQLite doesn't support locking clauses, pools of statements are checked with dry runs
+	stmt := db.Session(&gorm.Session{DryRun: true}).Clauses(clause.Locking{Strength: "UPDATE"}).Find(&[]ResolverUser{}).Statement
+	if pool, ok := stmt.ConnPool.(*resolver.Pool); !ok || pool.ConnPool != source {
+		t.Errorf("locking queries should be routed to source, got %#v", stmt.ConnPool)
+	}
+
+	if err := db.Create(&ResolverUser{Name: "created"}).Error; err != nil {
+		t.Fatalf("failed to create, got error %v", err)
+	}
+
+	if err := db.Model(&ResolverUser{}).Where("name = ?", "source").Update("name", "updated").Error; err != nil {
+		t.Fatalf("failed to update, got error %v", err)
+	}
+
+	if err := db.Exec("DELETE FROM resolver_users WHERE name = ?", "replica1").Error; err != nil {
+		t.Fatalf("failed to exec, got error %v", err)
+	}
+
+	if result := names(db.Clauses(resolver.UseSource())); len(result) != 2 || result[0] != "updated" || result[1] != "created" {
+		t.Errorf("writes should be routed to source, got %v", result)
+	}
+
+	if result := names(replica1); len(result) != 1 || result[0] != "replica1" {
+		t.Errorf("writes should not be routed to replicas, got %v", result)
+	}
+
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if result := names(tx); len(result) != 2 {
+			t.Errorf("queries in transactions should be routed to source, got %v", result)
+		}
+		return tx.Create(&ResolverUser{Name: "transaction"}).Error
+	}); err != nil {
+		t.Fatalf("failed to run transaction, got error %v", err)
+	}
+
+	ctx := resolver.ReadYourWrites(context.Background())
+	if result := names(db.WithContext(ctx)); len(result) != 1 || result[0] == "updated" {
+		t.Errorf("queries should be routed to replicas before writes, got %v", result)
+	}
+
+	db.WithContext(ctx).Create(&ResolverUser{Name: "read_your_writes"})
+	if result := names(db.WithContext(ctx)); len(result) != 4 || result[3] != "read_your_writes" {
+		t.Errorf("queries should be routed to source after writes, got %v", result)
+	}
+
+	if result := names(db.WithContext(context.Background())); len(result) != 1 {
+		t.Errorf("queries of other contexts should be routed to replicas, got %v", result)
+	}
+
+	if result := names(db.WithContext(ctx).Clauses(resolver.UseReplica())); len(result) != 1 {
+		t.Errorf("queries using replica should be routed to replicas, got %v", result)
+	}
+
+	txCtx := resolver.ReadYourWrites(context.Background())
+	if err := db.WithContext(txCtx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&ResolverUser{Name: "transaction_writes"}).Error
+	}); err != nil {
+		t.Fatalf("failed to run transaction, got error %v", err)
+	}
+
+	var user ResolverUser
+	if err := db.WithContext(txCtx).Where("name = ?", "transaction_writes").First(&user).Error; err != nil {
+		t.Errorf("queries should be routed to source after writes in transactions, got error %v", err)
+	}
+
+	if sqlDB, err := db.DB(); err != nil || sqlDB.Ping() != nil {
+		t.Errorf("failed to get sql db of source, got error %v", err)
+	}
+}
+
+func TestResolverPolicy(t *testing.T) {
+	pools := []*resolver.Pool{{}, {}, {}}
+
+	policy := &resolver.RoundRobinPolicy{}
+	for i := 0; i < 6; i++ {
+		if pool := policy.Resolve(pools); pool != pools[i%3] {
+			t.Errorf("round robin policy should resolve pools in turn, got %p at %v", pool, i)
+		}
+	}
+
+	if pool := (resolver.LeastInFlightPolicy{}).Resolve(pools); pool != pools[0] {
+		t.Errorf("least in flight policy should resolve the first idle pool, got %p", pool)
+	}
+
+	for i := 0; i < 10; i++ {
+		if pool := (resolver.RandomPolicy{}).Resolve(pools); pool != pools[0] && pool != pools[1] && pool != pools[2] {
+			t.Errorf("random policy should resolve one of pools, got %p", pool)
+		}
+	}
+}
@@ -283,3 +283,65 @@ func TestSerializerWithAnyType(t *testing.T) {
 		t.Error("expected Data to be non-nil")
 	}
 }
+
+func TestCompressedJSONAndCSVSerializer(t *testing.T) {
+	type SerializerDocument struct {
+		ID       uint
+		Content  map[string]interface{} `gorm:"type:bytes;serializer:gzipjson"`
+		Job      *Job                   `gorm:"type:bytes;serializer:gzipjson"`
+		Tags     []string               `gorm:"serializer:csv"`
+		Roles    *Roles                 `gorm:"serializer:csv"`
+		Keywords []string               `gorm:"serializer:csv;not null"`
+	}
+
+	DB.Migrator().DropTable(&SerializerDocument{})
+	if err := DB.AutoMigrate(&SerializerDocument{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	docs := []SerializerDocument{{
+		Content:  map[string]interface{}{"body": strings.Repeat("jinzhu ", 100)},
+		Job:      &Job{Title: "programmer", Number: 1},
+		Tags:     []string{"go", "orm, sql", `"quoted"`},
+		Roles:    &Roles{""},
+		Keywords: []string{},
+	}, {}}
+	if err := DB.Create(&docs).Error; err != nil {
+		t.Fatalf("failed to create documents, got error %v", err)
+	}
+
+	var raws []struct {
+		Content  []byte
+		Tags     *string
+		Roles    *string
+		Keywords *string
+	}
+	DB.Table("serializer_documents").Order("id").Find(&raws)
+
+	if len(raws[0].Content) == 0 || len(raws[0].Content) > 100 {
+		t.Errorf("content should be compressed, got %v bytes", len(raws[0].Content))
+	}
+	AssertEqual(t, *raws[0].Tags, `go,"orm, sql","""quoted"""`)
+	if raws[1].Content != nil || raws[1].Tags != nil || raws[1].Roles != nil {
+		t.Errorf("nil values should be stored as NULL, got %+v", raws[1])
+	}
+	AssertEqual(t, *raws[1].Keywords, "")
+
+	var results []SerializerDocument
+	if err := DB.Order("id").Find(&results).Error; err != nil {
+		t.Fatalf("failed to query documents, got error %v", err)
+	}
+	AssertEqual(t, results[0], docs[0])
+	if results[1].Content != nil || results[1].Job != nil || results[1].Tags != nil {
+		t.Errorf("NULL values should be scanned as nil, got %+v", results[1])
+	}
+	AssertEqual(t, results[1].Keywords, []string{})
+
+	if err := DB.Model(&results[1]).Update("tags", []string{"updated"}).Error; err != nil {
+		t.Fatalf("failed to update tags, got error %v", err)
+	}
+
+	var result SerializerDocument
+	DB.First(&result, results[1].ID)
+	AssertEqual(t, result.Tags, []string{"updated"})
+}
@@ -0,0 +1,104 @@
+package tests_test
+
+import (
+	"context"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestUnion(t *testing.T) {
+	users := []User{
+		*GetUser("union_1", Config{}),
+		*GetUser("union_2", Config{}),
+		*GetUser("union_3", Config{}),
+		*GetUser("union_4", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age, users[3].Age = 10, 20, 30, 40
+	DB.Create(&users)
+
+	young := DB.Model(&User{}).Where("name LIKE ? AND age < ?", "union_%", 15)
+	old := DB.Model(&User{}).Where("name LIKE ? AND age > ?", "union_%", 25)
+
+	var results []User
+	if err := DB.Union(young, old).Order("age DESC").Find(&results).Error; err != nil {
+		t.Fatalf("failed to query union, got error %v", err)
+	}
+
+	if len(results) != 3 || results[0].Name != "union_4" || results[1].Name != "union_3" || results[2].Name != "union_1" {
+		t.Errorf("failed to query union, got %+v", results)
+	}
+
+	results = nil
+	if err := DB.Union(young, old).Order("age").Limit(1).Offset(1).Find(&results).Error; err != nil {
+		t.Fatalf("failed to query union with limit, got error %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "union_3" {
+		t.Errorf("failed to query union with limit, got %+v", results)
+	}
+
+	var count int64
+	if err := DB.UnionAll(young, young, old).Count(&count).Error; err != nil || count != 4 {
+		t.Errorf("failed to count union all, got count %v, error %v", count, err)
+	}
+
+	if err := DB.Model(&User{}).Union(young, young, old).Count(&count).Error; err != nil || count != 3 {
+		t.Errorf("failed to count union, got count %v, error %v", count, err)
+	}
+
+	var names []string
+	all := DB.Model(&User{}).Select("name").Where("name LIKE ?", "union_%")
+	if err := DB.Except(all, old.Select("name")).Order("name").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("failed to query except, got error %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "union_1" || names[1] != "union_2" {
+		t.Errorf("failed to query except, got %v", names)
+	}
+
+	names = nil
+	if err := DB.Intersect(all, old.Select("name")).Order("name").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("failed to query intersect, got error %v", err)
+	}
+
+	if len(names) != 2 || names[0] != "union_3" || names[1] != "union_4" {
+		t.Errorf("failed to query intersect, got %v", names)
+	}
+
+	if err := DB.Union(young).Find(&results).Error; err == nil {
+		t.Errorf("should returns error for union with a single query")
+	}
+}
+
+func TestUnionToSQL(t *testing.T) {
+	sql := DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Union(DB.Model(&User{}).Where("age < ?", 18), DB.Model(&User{}).Where("name = ?", "union")).Order("name").Find(&[]User{})
+	})
+
+	assertEqualSQL(t, `SELECT * FROM (SELECT * FROM "users" WHERE age < 18 AND "users"."deleted_at" IS NULL UNION SELECT * FROM "users" WHERE name = "union" AND "users"."deleted_at" IS NULL) AS "users" ORDER BY name`, sql)
+}
+
+func TestGenericsUnion(t *testing.T) {
+	ctx := context.Background()
+	users := []User{
+		*GetUser("generics_union_1", Config{}),
+		*GetUser("generics_union_2", Config{}),
+		*GetUser("generics_union_3", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age = 10, 20, 30
+	DB.Create(&users)
+
+	results, err := gorm.G[User](DB).Where("name = ?", "generics_union_1").
+		Union(gorm.G[User](DB).Where("name = ?", "generics_union_3")).Order("age DESC").Find(ctx)
+	if err != nil || len(results) != 2 || results[0].Name != "generics_union_3" || results[1].Name != "generics_union_1" {
+		t.Errorf("failed to query union through generics, got %+v, error %v", results, err)
+	}
+
+	count, err := gorm.G[User](DB).Where("name LIKE ?", "generics_union_%").
+		Except(gorm.G[User](DB).Where("age > ?", 15)).Count(ctx, "*")
+	if err != nil || count != 1 {
+		t.Errorf("failed to count except through generics, got %v, error %v", count, err)
+	}
+}
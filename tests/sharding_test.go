@@ -0,0 +1,253 @@
+package tests_test
+
+import (
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/sharding"
+)
+
+type ShardingOrder struct {
+	ID       uint
+	TenantID uint
+	Product  string
+	Amount   int
+}
+
+type ShardingOrderItem struct {
+	ID       uint
+	TenantID uint
+	OrderID  uint
+	Order    ShardingOrder
+	Name     string
+}
+
+func openShardingDB(t *testing.T, file string, plugin *sharding.Sharding) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(file), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open %v, got error %v", file, err)
+	}
+
+	if err = db.Use(plugin); err != nil {
+		t.Fatalf("failed to use sharding, got error %v", err)
+	}
+	return db
+}
+
+func TestSharding(t *testing.T) {
+	db := openShardingDB(t, filepath.Join(t.TempDir(), "sharding.db"), &sharding.Sharding{Rules: map[string]sharding.Rule{
+		"sharding_orders":      {ShardKey: "tenant_id", Algorithm: sharding.Hash{Count: 4}},
+		"sharding_order_items": {ShardKey: "tenant_id", Algorithm: sharding.Hash{Count: 4}},
+	}})
+
+	if err := db.AutoMigrate(&ShardingOrder{}, &ShardingOrderItem{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	for _, table := range []string{"sharding_orders_0", "sharding_orders_3", "sharding_order_items_1"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %v of shard should be created", table)
+		}
+	}
+
+	if db.Migrator().HasTable("sharding_orders") {
+		t.Errorf("table without shard should not be created")
+	}
+
+	orders := []ShardingOrder{
+		{ID: 1, TenantID: 1, Product: "a", Amount: 10},
+		{ID: 2, TenantID: 2, Product: "b", Amount: 40},
+		{ID: 3, TenantID: 5, Product: "c", Amount: 30},
+		{ID: 4, TenantID: 6, Product: "d", Amount: 20},
+		{ID: 5, TenantID: 5, Product: "e", Amount: 50},
+	}
+	for _, order := range orders {
+		if err := db.Create(&order).Error; err != nil {
+			t.Fatalf("failed to create order, got error %v", err)
+		}
+	}
+
+	if err := db.Create(&[]ShardingOrder{{ID: 6, TenantID: 1}, {ID: 7, TenantID: 2}}).Error; !errors.Is(err, sharding.ErrMixedShards) {
+		t.Errorf("should returns mixed shards error, got %v", err)
+	}
+
+	if err := db.Create(&ShardingOrder{ID: 8}).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns missing shard key error, got %v", err)
+	}
+
+	var count int64
+	db.Table("sharding_orders_1").Count(&count)
+	if count != 3 {
+		t.Errorf("orders of tenant 1 and 5 should be created in shard 1, got %v", count)
+	}
+
+	var results []ShardingOrder
+	if err := db.Where("tenant_id = ?", 5).Order("id").Find(&results).Error; err != nil || len(results) != 2 || results[0].ID != 3 || results[1].ID != 5 {
+		t.Errorf("failed to query shard, got %+v, error %v", results, err)
+	}
+
+	var order ShardingOrder
+	if err := db.Where(&ShardingOrder{TenantID: 6}).First(&order).Error; err != nil || order.ID != 4 {
+		t.Errorf("failed to query shard with struct conditions, got %+v, error %v", order, err)
+	}
+
+	results = nil
+	if err := db.Order("amount desc").Limit(2).Offset(1).Find(&results).Error; err != nil || len(results) != 2 || results[0].ID != 2 || results[1].ID != 3 {
+		t.Errorf("failed to query all shards, got %+v, error %v", results, err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Count(&count).Error; err != nil || count != 5 {
+		t.Errorf("failed to count all shards, got %v, error %v", count, err)
+	}
+
+	var products []string
+	if err := db.Model(&ShardingOrder{}).Order("product desc").Pluck("product", &products).Error; err != nil || len(products) != 5 || products[0] != "e" || products[4] != "a" {
+		t.Errorf("failed to pluck all shards, got %v, error %v", products, err)
+	}
+
+	order = ShardingOrder{}
+	if err := db.Where("amount > ?", 35).Order("amount").First(&order).Error; err != nil || order.ID != 2 {
+		t.Errorf("failed to find first record of all shards, got %+v, error %v", order, err)
+	}
+
+	if err := db.Where("amount > ?", 100).First(&ShardingOrder{}).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("should returns record not found error, got %v", err)
+	}
+
+	order = ShardingOrder{}
+	if err := db.Where("amount > ?", 15).First(&order).Error; err != nil || order.ID != 2 {
+		t.Errorf("first record of all shards should be ordered by primary key, got %+v, error %v", order, err)
+	}
+
+	order = ShardingOrder{}
+	if err := db.Where("amount < ?", 35).Last(&order).Error; err != nil || order.ID != 4 {
+		t.Errorf("last record of all shards should be ordered by primary key, got %+v, error %v", order, err)
+	}
+
+	var total int
+	if err := db.Model(&ShardingOrder{}).Select("SUM(amount)").Find(&total).Error; err != nil || total != 150 {
+		t.Errorf("failed to sum all shards, got %v, error %v", total, err)
+	}
+
+	var maxAmount int
+	if err := db.Model(&ShardingOrder{}).Select("MAX(amount)").Find(&maxAmount).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns error for aggregates which can't be merged, got %v", err)
+	}
+
+	results = nil
+	if err := db.Clauses(sharding.UseShard(2)).Find(&results).Error; err != nil || len(results) != 2 {
+		t.Errorf("failed to query specified shard, got %+v, error %v", results, err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Where("tenant_id = ?", 5).Update("amount", 60).Error; err != nil {
+		t.Errorf("failed to update shard, got error %v", err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Where("id = ?", 1).Update("amount", 60).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns missing shard key error for updates, got %v", err)
+	}
+
+	if err := db.Where("tenant_id = ?", nil).Find(&results).Error; !errors.Is(err, sharding.ErrInvalidShardKey) {
+		t.Errorf("should returns invalid shard key error for nil shard keys, got %v", err)
+	}
+
+	// records matching OR conditions may be in any shard
+	results = nil
+	if err := db.Where("tenant_id = ?", 1).Or("tenant_id = ?", 2).Order("id").Find(&results).Error; err != nil || len(results) != 2 || results[0].ID != 1 || results[1].ID != 2 {
+		t.Errorf("queries with OR conditions should be executed on all shards, got %+v, error %v", results, err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Where("tenant_id = ?", 1).Or("tenant_id = ?", 2).Update("amount", 0).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns missing shard key error for updates with OR conditions, got %v", err)
+	}
+
+	if err := db.Where("tenant_id = ?", 1).Or("tenant_id = ?", 2).Delete(&ShardingOrder{}).Error; !errors.Is(err, sharding.ErrMissingShardKey) {
+		t.Errorf("should returns missing shard key error for deletes with OR conditions, got %v", err)
+	}
+
+	if err := db.Delete(&ShardingOrder{ID: 4, TenantID: 6}).Error; err != nil {
+		t.Errorf("failed to delete from shard, got error %v", err)
+	}
+
+	if err := db.Model(&ShardingOrder{}).Count(&count).Error; err != nil || count != 4 {
+		t.Errorf("failed to count all shards after deleting, got %v, error %v", count, err)
+	}
+
+	if err := db.Create(&ShardingOrderItem{ID: 1, TenantID: 5, OrderID: 3, Name: "item"}).Error; err != nil {
+		t.Fatalf("failed to create order item, got error %v", err)
+	}
+
+	var item ShardingOrderItem
+	if err := db.Joins("Order").Where(&ShardingOrderItem{TenantID: 5}).First(&item).Error; err != nil || item.Order.ID != 3 || item.Order.Amount != 60 {
+		t.Errorf("failed to join tables of the same shard, got %+v, error %v", item, err)
+	}
+
+	sql := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Joins("Order").Where(&ShardingOrderItem{TenantID: 5}).Find(&[]ShardingOrderItem{})
+	})
+	assertEqualSQL(t, "SELECT `sharding_order_items_1`.`id`,`sharding_order_items_1`.`tenant_id`,`sharding_order_items_1`.`order_id`,`sharding_order_items_1`.`name`,`Order`.`id` AS `Order__id`,`Order`.`tenant_id` AS `Order__tenant_id`,`Order`.`product` AS `Order__product`,`Order`.`amount` AS `Order__amount` FROM `sharding_order_items_1` LEFT JOIN `sharding_orders_1` `Order` ON `sharding_order_items_1`.`order_id` = `Order`.`id` WHERE `sharding_order_items_1`.`tenant_id` = 5", sql)
+
+	if err := db.Migrator().DropTable(&ShardingOrder{}, "sharding_order_items"); err != nil || db.Migrator().HasTable("sharding_orders_0") || db.Migrator().HasTable("sharding_order_items_0") {
+		t.Errorf("failed to drop tables of shards, got error %v", err)
+	}
+}
+
+func TestShardingConnPools(t *testing.T) {
+	dir := t.TempDir()
+	db0, _ := gorm.Open(sqlite.Open(filepath.Join(dir, "shard0.db")), &gorm.Config{Logger: DB.Logger})
+	db1, _ := gorm.Open(sqlite.Open(filepath.Join(dir, "shard1.db")), &gorm.Config{Logger: DB.Logger})
+
+	db := openShardingDB(t, filepath.Join(dir, "sharding.db"), &sharding.Sharding{Rules: map[string]sharding.Rule{
+		"sharding_orders": {
+			ShardKey:    "tenant_id",
+			Algorithm:   sharding.Range{Bounds: []int64{100}},
+			TableFormat: "%s_%02d",
+			ConnPools:   []gorm.ConnPool{db0.ConnPool, db1.ConnPool},
+		},
+	}})
+
+	if err := db.AutoMigrate(&ShardingOrder{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if !db0.Migrator().HasTable("sharding_orders_00") || !db1.Migrator().HasTable("sharding_orders_01") || db0.Migrator().HasTable("sharding_orders_01") {
+		t.Fatalf("tables of shards should be created in their connection pools")
+	}
+
+	db.Create(&ShardingOrder{ID: 1, TenantID: 10, Amount: 1})
+	db.Create(&ShardingOrder{ID: 2, TenantID: 100, Amount: 2})
+	db.Create(&ShardingOrder{ID: 3, TenantID: 200, Amount: 3})
+
+	var count int64
+	if db1.Table("sharding_orders_01").Count(&count); count != 2 {
+		t.Errorf("orders should be created in the connection pool of the shard, got %v", count)
+	}
+
+	var results []ShardingOrder
+	if err := db.Order("amount desc").Find(&results).Error; err != nil || len(results) != 3 || results[0].ID != 3 || results[2].ID != 1 {
+		t.Errorf("failed to query all shards, got %+v, error %v", results, err)
+	}
+
+	if err := db.Where("tenant_id = ?", 10).Find(&results).Error; err != nil || len(results) != 1 || results[0].ID != 1 {
+		t.Errorf("failed to query shard, got %+v, error %v", results, err)
+	}
+}
+
+func TestShardingHash(t *testing.T) {
+	algorithm := sharding.Hash{Count: 3}
+	for _, value := range []interface{}{int64(math.MinInt64), int64(math.MaxInt64), -5, uint64(math.MaxUint64), "tenant"} {
+		if shard, err := algorithm.Shard(value); err != nil || shard < 0 || shard >= 3 {
+			t.Errorf("shard of %v should be in [0, 3), got %v, error %v", value, shard, err)
+		}
+	}
+
+	for _, value := range []interface{}{nil, (*string)(nil)} {
+		if _, err := algorithm.Shard(value); !errors.Is(err, sharding.ErrInvalidShardKey) {
+			t.Errorf("should returns invalid shard key error for %#v, got %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/plugin/tenancy"
+	. "gorm.io/gorm/utils/tests"
+)
+
+type TenancyNote struct {
+	ID       uint
+	TenantID string `gorm:"tenant"`
+	Title    string
+	Tasks    []TenancyTask `gorm:"foreignKey:NoteID"`
+}
+
+type TenancyTask struct {
+	ID     uint
+	Org    string
+	NoteID uint
+	Name   string
+}
+
+func (TenancyTask) TenantColumn() string {
+	return "org"
+}
+
+func openTenancyDB(t *testing.T, plugin *tenancy.Tenancy) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "tenancy.db")), &gorm.Config{Logger: DB.Logger})
+	if err != nil {
+		t.Fatalf("failed to open db, got error %v", err)
+	}
+
+	if err = db.Use(plugin); err != nil {
+		t.Fatalf("failed to use tenancy, got error %v", err)
+	}
+	return db
+}
+
+func TestTenancy(t *testing.T) {
+	db := openTenancyDB(t, &tenancy.Tenancy{})
+	if err := db.AutoMigrate(&TenancyNote{}, &TenancyTask{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	if err := db.Create(&TenancyNote{Title: "no tenant"}).Error; !errors.Is(err, tenancy.ErrMissingTenant) {
+		t.Errorf("should returns ErrMissingTenant without tenants, got %v", err)
+	}
+
+	var (
+		acme   = db.WithContext(tenancy.WithTenant(context.Background(), "acme"))
+		globex = db.WithContext(tenancy.WithTenant(context.Background(), "globex"))
+		notes  = []TenancyNote{{Title: "a1", Tasks: []TenancyTask{{Name: "t1"}, {Name: "t2"}}}, {Title: "a2"}}
+		other  = TenancyNote{Title: "g1", Tasks: []TenancyTask{{Name: "t3"}}}
+	)
+
+	if err := acme.Create(&notes).Error; err != nil {
+		t.Fatalf("failed to create notes, got error %v", err)
+	}
+	AssertEqual(t, notes[0].TenantID, "acme")
+	AssertEqual(t, notes[0].Tasks[1].Org, "acme")
+
+	if err := globex.Create(&other).Error; err != nil {
+		t.Fatalf("failed to create notes, got error %v", err)
+	}
+
+	if err := acme.Create(&TenancyNote{TenantID: "globex", Title: "foreign"}).Error; !errors.Is(err, tenancy.ErrForeignTenant) {
+		t.Errorf("should returns ErrForeignTenant when creating records of other tenants, got %v", err)
+	}
+
+	if err := acme.Model(&TenancyNote{}).Create(map[string]interface{}{"Title": "map"}).Error; err != nil {
+		t.Errorf("failed to create map, got error %v", err)
+	}
+
+	// queries
+	var found []TenancyNote
+	if err := acme.Preload("Tasks").Order("id").Find(&found).Error; err != nil {
+		t.Fatalf("failed to find notes, got error %v", err)
+	}
+	AssertEqual(t, len(found), 3)
+	AssertEqual(t, len(found[0].Tasks), 2)
+	AssertEqual(t, found[2].TenantID, "acme")
+
+	var count int64
+	globex.Model(&TenancyTask{}).Count(&count)
+	AssertEqual(t, count, 1)
+
+	if err := acme.First(&TenancyNote{}, other.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("records of other tenants should not be found, got %v", err)
+	}
+
+	if err := acme.Unscoped().First(&TenancyNote{}, other.ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Unscoped should not query records of other tenants, got %v", err)
+	}
+
+	// OR conditions don't match records of other tenants
+	if err := acme.Where("title = ?", "g1").Or("title = ?", "a1").Find(&found).Error; err != nil {
+		t.Fatalf("failed to find notes with OR conditions, got error %v", err)
+	}
+	AssertEqual(t, len(found), 1)
+	AssertEqual(t, found[0].Title, "a1")
+
+	if err := db.Find(&found).Error; !errors.Is(err, tenancy.ErrMissingTenant) {
+		t.Errorf("should returns ErrMissingTenant for queries without tenants, got %v", err)
+	}
+
+	db.Clauses(tenancy.CrossTenant()).Model(&TenancyNote{}).Count(&count)
+	AssertEqual(t, count, 4)
+
+	// updates
+	if result := acme.Model(&TenancyNote{}).Where("id = ?", other.ID).Update("title", "hijacked"); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("records of other tenants should not be updated, got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if err := acme.Model(&notes[0]).Update("tenant_id", "globex").Error; !errors.Is(err, tenancy.ErrForeignTenant) {
+		t.Errorf("should returns ErrForeignTenant when moving records to other tenants, got %v", err)
+	}
+
+	other.Title = "saved"
+	if err := acme.Save(&other).Error; !errors.Is(err, tenancy.ErrForeignTenant) {
+		t.Errorf("should returns ErrForeignTenant when saving records of other tenants, got %v", err)
+	}
+
+	// upserts of Save don't update records of other tenants
+	if err := acme.Save(&TenancyNote{ID: other.ID, Title: "hijacked"}).Error; err != nil {
+		t.Errorf("failed to save note, got error %v", err)
+	}
+
+	if err := acme.Save(&[]TenancyNote{{ID: other.ID, Title: "hijacked"}}).Error; err != nil {
+		t.Errorf("failed to save notes, got error %v", err)
+	}
+
+	var saved TenancyNote
+	globex.First(&saved, other.ID)
+	AssertEqual(t, saved.TenantID, "globex")
+	AssertEqual(t, saved.Title, "g1")
+
+	if err := acme.Model(&TenancyNote{}).Update("title", "all").Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("tenant conditions should not allow global updates, got %v", err)
+	}
+
+	notes[1].Title = "a2 saved"
+	if err := acme.Save(&notes[1]).Error; err != nil {
+		t.Errorf("failed to save note, got error %v", err)
+	}
+
+	if result := acme.Model(&TenancyNote{}).Where("title = ?", "g1").Or("title = ?", "a1").Update("title", gorm.Expr("title")); result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("records of other tenants should not be updated with OR conditions, got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	// deletes
+	if result := acme.Where("title = ?", "g1").Or("title = ?", "unknown").Delete(&TenancyNote{}); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("records of other tenants should not be deleted with OR conditions, got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if result := acme.Delete(&TenancyNote{}, other.ID); result.Error != nil || result.RowsAffected != 0 {
+		t.Errorf("records of other tenants should not be deleted, got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	if result := globex.Where("name <> ?", "").Delete(&TenancyTask{}); result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("failed to delete tasks, got %v, rows affected %v", result.Error, result.RowsAffected)
+	}
+
+	var titles []string
+	db.Clauses(tenancy.CrossTenant()).Model(&TenancyNote{}).Order("id").Pluck("title", &titles)
+	AssertEqual(t, titles, []string{"a1", "a2 saved", "g1", "map"})
+}
+
+func TestTenancySchemaPerTenant(t *testing.T) {
+	plugin := &tenancy.Tenancy{
+		Strategy:    tenancy.SchemaPerTenant,
+		TablePrefix: func(tenant interface{}) string { return tenant.(string) + "_" },
+	}
+	db := openTenancyDB(t, plugin)
+
+	for _, tenant := range []string{"acme", "globex"} {
+		if err := plugin.Migrate(db, tenant, &TenancyNote{}); err != nil {
+			t.Fatalf("failed to migrate tables of tenant %v, got error %v", tenant, err)
+		}
+	}
+
+	if !db.Migrator().HasTable("acme_tenancy_notes") || db.Migrator().HasTable("tenancy_notes") {
+		t.Fatalf("tables of tenants should be prefixed")
+	}
+
+	acme := db.WithContext(tenancy.WithTenant(context.Background(), "acme"))
+	globex := db.WithContext(tenancy.WithTenant(context.Background(), "globex"))
+	acme.Create(&[]TenancyNote{{Title: "a1"}, {Title: "a2"}})
+	globex.Create(&TenancyNote{Title: "g1"})
+
+	var count int64
+	db.Table("acme_tenancy_notes").Count(&count)
+	AssertEqual(t, count, 2)
+
+	var notes []TenancyNote
+	if err := globex.Find(&notes).Error; err != nil {
+		t.Fatalf("failed to find notes, got error %v", err)
+	}
+	AssertEqual(t, len(notes), 1)
+	AssertEqual(t, notes[0].Title, "g1")
+	AssertEqual(t, notes[0].TenantID, "globex")
+
+	// statements of chains executed more than once
+	chain := acme.Model(&TenancyNote{}).Where("title <> ?", "")
+	if err := chain.Count(&count).Error; err != nil {
+		t.Fatalf("failed to count notes, got error %v", err)
+	}
+	AssertEqual(t, count, 2)
+
+	if err := chain.Find(&notes).Error; err != nil {
+		t.Fatalf("failed to find notes with the chain, got error %v", err)
+	}
+	AssertEqual(t, len(notes), 2)
+}
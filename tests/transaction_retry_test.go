@@ -0,0 +1,62 @@
+package tests_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// mysqlErrorTranslator translates MySQL deadlocks like the MySQL dialector
+type mysqlErrorTranslator struct {
+	gorm.Dialector
+}
+
+func (mysqlErrorTranslator) Translate(err error) error {
+	if mysqlErr, ok := err.(*mysql.MySQLError); ok && mysqlErr.Number == 1213 {
+		return gorm.ErrDeadlock
+	}
+	return err
+}
+
+func TestTransactionRetryDriverErrors(t *testing.T) {
+	db := DB.Session(&gorm.Session{TransactionRetry: &gorm.RetryPolicy{MaxAttempts: 2}})
+	db.Dialector = mysqlErrorTranslator{Dialector: db.Dialector}
+
+	for _, driverErr := range []error{
+		&pgconn.PgError{Code: "40001", Message: "could not serialize access due to concurrent update"},
+		fmt.Errorf("failed to transfer: %w", &pgconn.PgError{Code: "40P01", Message: "deadlock detected"}),
+		errors.Join(errors.New("failed to transfer"), &pgconn.PgError{Code: "40001", Message: "could not serialize access"}),
+		&mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock; try restarting transaction"},
+		fmt.Errorf("failed to transfer: %w", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"}),
+	} {
+		attempts := 0
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if attempts++; attempts < 2 {
+				return driverErr
+			}
+			return nil
+		})
+		if err != nil || attempts != 2 {
+			t.Errorf("transaction failed with %v should be retried, got attempts %v, error %v", driverErr, attempts, err)
+		}
+	}
+
+	for _, driverErr := range []error{
+		&pgconn.PgError{Code: "23505", Message: "duplicate key value violates unique constraint"},
+		&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"},
+		&mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"},
+	} {
+		attempts := 0
+		err := db.Transaction(func(tx *gorm.DB) error {
+			attempts++
+			return driverErr
+		})
+		if !errors.Is(err, driverErr) || attempts != 1 {
+			t.Errorf("transaction failed with %v should not be retried, got attempts %v, error %v", driverErr, attempts, err)
+		}
+	}
+}
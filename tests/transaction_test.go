@@ -490,3 +490,347 @@ func TestTransactionWithDefaultTimeout(t *testing.T) {
 		t.Errorf("should return error when transaction timeout, got error %v", err)
 	}
 }
+
+type retryErrorTranslator struct {
+	gorm.Dialector
+	from error
+	to   error
+}
+
+func (translator retryErrorTranslator) Translate(err error) error {
+	if errors.Is(err, translator.from) {
+		return translator.to
+	}
+	return err
+}
+
+func TestTransactionRetry(t *testing.T) {
+	retry := &gorm.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Jitter: 0.5}
+	db := DB.Session(&gorm.Session{TransactionRetry: retry})
+
+	attempts := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(GetUser("transaction-retry", Config{})).Error; err != nil {
+			return err
+		}
+
+		// nested transactions are retried with their outermost transactions
+		return tx.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(GetUser("transaction-retry-nested", Config{})).Error; err != nil {
+				return err
+			}
+
+			if attempts < 3 {
+				return gorm.ErrSerializationFailure
+			}
+			return nil
+		})
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("transaction should be committed at the 3rd attempt, got attempts %v, error %v", attempts, err)
+	}
+
+	for _, name := range []string{"transaction-retry", "transaction-retry-nested"} {
+		var count int64
+		DB.Model(&User{}).Where("name = ?", name).Count(&count)
+		AssertEqual(t, count, int64(1))
+	}
+
+	// errors are returned after max attempts
+	attempts = 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return gorm.ErrDeadlock
+	})
+	if !errors.Is(err, gorm.ErrDeadlock) || attempts != 3 {
+		t.Fatalf("transaction should fail after 3 attempts, got attempts %v, error %v", attempts, err)
+	}
+
+	// other errors are not retried
+	attempts = 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return gorm.ErrRecordNotFound
+	})
+	if !errors.Is(err, gorm.ErrRecordNotFound) || attempts != 1 {
+		t.Fatalf("transaction should not be retried, got attempts %v, error %v", attempts, err)
+	}
+
+	// transactions without retry policies are not retried
+	attempts = 0
+	DB.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return gorm.ErrDeadlock
+	})
+	AssertEqual(t, attempts, 1)
+}
+
+func TestTransactionRetryClassifier(t *testing.T) {
+	driverErr := errors.New("driver: deadlock found when trying to get lock")
+
+	// driver errors are classified with the error translator of the dialector
+	db := DB.Session(&gorm.Session{TransactionRetry: &gorm.RetryPolicy{MaxAttempts: 2}})
+	db.Dialector = retryErrorTranslator{Dialector: db.Dialector, from: driverErr, to: gorm.ErrDeadlock}
+
+	attempts := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if attempts++; attempts < 2 {
+			return driverErr
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("transaction should be retried once, got attempts %v, error %v", attempts, err)
+	}
+
+	// custom classifier
+	db = DB.Session(&gorm.Session{TransactionRetry: &gorm.RetryPolicy{
+		MaxAttempts: 5,
+		Retryable:   func(err error) bool { return errors.Is(err, driverErr) },
+	}})
+
+	attempts = 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if attempts++; attempts < 4 {
+			return driverErr
+		}
+		return nil
+	})
+	if err != nil || attempts != 4 {
+		t.Fatalf("transaction should be retried 3 times, got attempts %v, error %v", attempts, err)
+	}
+
+	// retries stop when the context is done
+	ctx, cancel := context.WithCancel(context.Background())
+	db = DB.WithContext(ctx).Session(&gorm.Session{TransactionRetry: &gorm.RetryPolicy{MaxAttempts: 5, Backoff: time.Hour}})
+
+	attempts = 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		cancel()
+		return gorm.ErrSerializationFailure
+	})
+	if !errors.Is(err, gorm.ErrSerializationFailure) || attempts != 1 {
+		t.Fatalf("transaction should not be retried after the context is done, got attempts %v, error %v", attempts, err)
+	}
+}
+
+func TestTransactionHooks(t *testing.T) {
+	var events []string
+	record := func(event string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			events = append(events, event)
+		}
+	}
+
+	// commit
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(record("commit")).AfterRollback(record("rollback"))
+		tx.Session(&gorm.Session{NewDB: true}).AfterCommit(record("commit-2"))
+
+		// savepoints rolled back discard hooks registered in them
+		tx.Transaction(func(tx *gorm.DB) error {
+			tx.AfterCommit(record("nested-commit")).AfterRollback(record("nested-rollback"))
+			return errors.New("nested error")
+		})
+
+		tx.Transaction(func(tx *gorm.DB) error {
+			tx.AfterCommit(record("nested-commit-2"))
+			return nil
+		})
+
+		AssertEqual(t, len(events), 0)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to commit transaction, got error %v", err)
+	}
+	AssertEqual(t, events, []string{"commit", "commit-2", "nested-commit-2"})
+
+	// rollback
+	events = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.AfterCommit(record("commit")).AfterRollback(record("rollback"))
+		return errors.New("error")
+	})
+	AssertEqual(t, events, []string{"rollback"})
+
+	// begin, commit
+	events = nil
+	tx := DB.Begin()
+	tx.AfterCommit(record("commit")).AfterRollback(record("rollback"))
+	tx.Commit()
+	tx.Rollback()
+	AssertEqual(t, events, []string{"commit"})
+
+	// savepoints created before hooks are registered
+	events = nil
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.SavePoint("before_hooks")
+		tx.AfterCommit(record("discarded"))
+		tx.RollbackTo("before_hooks")
+		tx.AfterCommit(record("commit"))
+		return nil
+	})
+	AssertEqual(t, events, []string{"commit"})
+
+	// out of transactions
+	events = nil
+	DB.AfterCommit(record("commit")).AfterRollback(record("rollback"))
+	AssertEqual(t, events, []string{"commit"})
+
+	// transactions of uncomparable types
+	events = nil
+	tx = DB.WithContext(context.Background())
+	tx.Statement.ConnPool = uncomparableTx{ConnPool: DB.ConnPool}
+	if err := tx.AfterCommit(record("commit")).Error; !errors.Is(err, gorm.ErrInvalidTransaction) {
+		t.Errorf("should returns invalid transaction error, got %v", err)
+	}
+	AssertEqual(t, len(events), 0)
+}
+
+type uncomparableTx struct {
+	gorm.ConnPool
+	savePoints []string
+}
+
+func (uncomparableTx) Commit() error {
+	return nil
+}
+
+func (uncomparableTx) Rollback() error {
+	return nil
+}
+
+type TransactionHookUser struct {
+	ID         uint
+	Name       string
+	Committed  int  `gorm:"-"`
+	RolledBack int  `gorm:"-"`
+	Found      bool `gorm:"-"`
+}
+
+func (u *TransactionHookUser) AfterCommit(tx *gorm.DB) error {
+	u.Committed++
+	// hooks are called out of the completed transaction
+	u.Found = tx.First(&TransactionHookUser{}, u.ID).Error == nil
+	return nil
+}
+
+func (u *TransactionHookUser) AfterRollback(tx *gorm.DB) error {
+	u.RolledBack++
+	return nil
+}
+
+func TestTransactionModelHooks(t *testing.T) {
+	DB.Migrator().DropTable(&TransactionHookUser{})
+	if err := DB.AutoMigrate(&TransactionHookUser{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	// default transactions
+	user := TransactionHookUser{Name: "transaction-hook"}
+	DB.Create(&user)
+	AssertEqual(t, user.Committed, 1)
+	AssertEqual(t, user.Found, true)
+
+	DB.Model(&user).Update("name", "transaction-hook-2")
+	AssertEqual(t, user.Committed, 2)
+
+	// hooks of statements in rolled back transactions
+	users := []TransactionHookUser{{Name: "transaction-hook-3"}, {Name: "transaction-hook-4"}}
+	DB.Transaction(func(tx *gorm.DB) error {
+		tx.Create(&users)
+		tx.Delete(&user)
+		return errors.New("error")
+	})
+
+	for _, u := range append(users, user) {
+		AssertEqual(t, u.RolledBack, 1)
+	}
+	AssertEqual(t, users[0].Committed, 0)
+	AssertEqual(t, user.Committed, 2)
+
+	// hooks are skipped with SkipHooks
+	DB.Session(&gorm.Session{SkipHooks: true}).Delete(&user)
+	AssertEqual(t, user.Committed, 2)
+}
+
+func TestContextTransaction(t *testing.T) {
+	db := DB.Session(&gorm.Session{})
+	db.Config.JoinContextTransaction = true
+
+	exists := func(name string) bool {
+		var count int64
+		DB.Model(&User{}).Where("name = ?", name).Count(&count)
+		return count > 0
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ctx := gorm.ContextWithTx(context.Background(), tx)
+		if db.WithContext(ctx).Statement.ConnPool != tx.Statement.ConnPool {
+			t.Fatalf("session should join the transaction of the context")
+		}
+
+		if DB.WithContext(ctx).Statement.ConnPool == tx.Statement.ConnPool {
+			t.Fatalf("session should not join the transaction without JoinContextTransaction")
+		}
+
+		if err := db.WithContext(ctx).Create(GetUser("context-tx", Config{})).Error; err != nil {
+			return err
+		}
+
+		if err := gorm.G[User](db).Create(ctx, GetUser("context-tx-generics", Config{})); err != nil {
+			return err
+		}
+
+		var user User
+		if err := db.WithContext(ctx).Where("name = ?", "context-tx").First(&user).Error; err != nil {
+			t.Fatalf("should find the user in the transaction, got error %v", err)
+		}
+
+		// nested transactions use savepoints of the joined transaction
+		db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			tx.Create(GetUser("context-tx-savepoint", Config{}))
+			return errors.New("rollback to savepoint")
+		})
+
+		// or run in it directly with DisableNestedTransaction
+		db.WithContext(ctx).Session(&gorm.Session{DisableNestedTransaction: true}).Transaction(func(tx *gorm.DB) error {
+			tx.Create(GetUser("context-tx-disable-nested", Config{}))
+			return errors.New("no savepoint")
+		})
+
+		return errors.New("rollback")
+	})
+	if err == nil || err.Error() != "rollback" {
+		t.Fatalf("transaction should be rolled back, got error %v", err)
+	}
+
+	for _, name := range []string{"context-tx", "context-tx-generics", "context-tx-savepoint", "context-tx-disable-nested"} {
+		if exists(name) {
+			t.Errorf("user %v should be rolled back", name)
+		}
+	}
+
+	db.Transaction(func(tx *gorm.DB) error {
+		ctx := gorm.ContextWithTx(context.Background(), tx)
+		db.WithContext(ctx).Create(GetUser("context-tx-commit", Config{}))
+
+		db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			tx.Create(GetUser("context-tx-commit-savepoint", Config{}))
+			return errors.New("rollback to savepoint")
+		})
+
+		db.WithContext(ctx).Session(&gorm.Session{DisableNestedTransaction: true}).Transaction(func(tx *gorm.DB) error {
+			tx.Create(GetUser("context-tx-commit-disable-nested", Config{}))
+			return errors.New("no savepoint")
+		})
+		return nil
+	})
+
+	AssertEqual(t, exists("context-tx-commit"), true)
+	AssertEqual(t, exists("context-tx-commit-savepoint"), false)
+	AssertEqual(t, exists("context-tx-commit-disable-nested"), true)
+}
@@ -1,6 +1,7 @@
 package tests_test
 
 import (
+	"context"
 	"errors"
 	"regexp"
 	"sort"
@@ -931,3 +932,42 @@ func TestUpdateFrom(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateWithJoins(t *testing.T) {
+	users := []User{
+		*GetUser("update_joins_1", Config{Company: true}),
+		*GetUser("update_joins_2", Config{Company: true}),
+		*GetUser("update_joins_3", Config{}),
+	}
+	users[0].Company.Name = "update_joins_company"
+	DB.Create(&users)
+
+	result := DB.Model(&User{}).InnerJoins("Company").Where("Company.name = ?", "update_joins_company").Update("age", 66)
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Fatalf("failed to update with joins, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	var ages []uint
+	DB.Model(&User{}).Where("name LIKE ?", "update_joins_%").Order("name").Pluck("age", &ages)
+	if len(ages) != 3 || ages[0] != 66 || ages[1] == 66 || ages[2] == 66 {
+		t.Errorf("failed to update with joins, got ages %v", ages)
+	}
+
+	if err := DB.Model(&User{}).Joins("Company").Update("age", 10).Error; !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("should returns ErrMissingWhereClause when updating with joins only, got %v", err)
+	}
+
+	count, err := gorm.G[User](DB).Joins(clause.Has("Company"), func(db gorm.JoinBuilder, joinTable clause.Table, curTable clause.Table) error {
+		db.Where("?.name LIKE ?", joinTable, "company-update_joins_%")
+		return nil
+	}).Where("users.name LIKE ?", "update_joins_%").Update(context.Background(), "age", 77)
+	if err != nil || count != 1 {
+		t.Fatalf("failed to update with joins through generics, got rows affected %v, error %v", count, err)
+	}
+
+	var user User
+	DB.First(&user, users[1].ID)
+	if user.Age != 77 {
+		t.Errorf("failed to update with joins through generics, got age %v", user.Age)
+	}
+}
@@ -0,0 +1,171 @@
+package tests_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type VersionedAccount struct {
+	ID       uint
+	Name     string
+	Balance  int
+	Version  gorm.Version
+	Payments []VersionedPayment
+}
+
+type VersionedPayment struct {
+	ID                 uint
+	VersionedAccountID uint
+	Amount             int
+	Version            gorm.Version
+}
+
+func TestVersion(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedAccount{}, &VersionedPayment{})
+	if err := DB.AutoMigrate(&VersionedAccount{}, &VersionedPayment{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	account := VersionedAccount{Name: "version", Balance: 100}
+	if err := DB.Create(&account).Error; err != nil || account.Version.Int64 != 1 {
+		t.Fatalf("version of created record should be 1, got %+v, error %v", account.Version, err)
+	}
+
+	var stale VersionedAccount
+	DB.First(&stale, account.ID)
+
+	sql := DB.Session(&gorm.Session{DryRun: true}).Model(&account).Update("balance", 200).Statement.SQL.String()
+	if !regexp.MustCompile(`UPDATE .versioned_accounts. SET .balance.=.*,.version.=.* WHERE .versioned_accounts.\..version. = .* AND .id. = .*`).MatchString(sql) {
+		t.Errorf("invalid sql generated, got %v", sql)
+	}
+
+	if account.Version.Int64 != 1 {
+		t.Errorf("version should not be changed by dry run, got %+v", account.Version)
+	}
+
+	if err := DB.Model(&account).Update("balance", 200).Error; err != nil || account.Version.Int64 != 2 {
+		t.Fatalf("failed to update, got version %+v, error %v", account.Version, err)
+	}
+
+	if err := DB.Model(&stale).Update("balance", 300).Error; !errors.Is(err, gorm.ErrOptimisticLockConflict) {
+		t.Errorf("should returns optimistic lock conflict error, got %v", err)
+	}
+
+	if stale.Version.Int64 != 1 {
+		t.Errorf("version of stale record should be restored, got %+v", stale)
+	}
+
+	stale.Name = "stale"
+	if err := DB.Save(&stale).Error; !errors.Is(err, gorm.ErrOptimisticLockConflict) {
+		t.Errorf("should returns optimistic lock conflict error when saving, got %v", err)
+	}
+
+	account.Name = "saved"
+	if err := DB.Save(&account).Error; err != nil || account.Version.Int64 != 3 {
+		t.Errorf("failed to save, got version %+v, error %v", account.Version, err)
+	}
+
+	if err := DB.Model(&account).Select("Name").Updates(VersionedAccount{Name: "selected"}).Error; err != nil || account.Version.Int64 != 4 {
+		t.Errorf("failed to update selected fields, got version %+v, error %v", account.Version, err)
+	}
+
+	if err := DB.Model(&VersionedAccount{}).Where("id = ?", account.ID).Update("balance", 400).Error; err != nil {
+		t.Errorf("failed to update without version, got error %v", err)
+	}
+
+	var result VersionedAccount
+	if DB.First(&result, account.ID); result.Version.Int64 != 5 || result.Balance != 400 || result.Name != "selected" {
+		t.Errorf("version should be increased by updates without versions, got %+v", result)
+	}
+
+	if err := DB.Model(&account).Omit("version").Update("balance", 500).Error; err != nil || account.Version.Int64 != 4 {
+		t.Errorf("updates omitting version should not be locked, got version %+v, error %v", account.Version, err)
+	}
+
+	ctx := context.Background()
+	if _, err := gorm.G[VersionedAccount](DB).Where("id = ?", account.ID).Updates(ctx, VersionedAccount{Name: "generics", Version: account.Version}); !errors.Is(err, gorm.ErrOptimisticLockConflict) {
+		t.Errorf("should returns optimistic lock conflict error for generics updates, got %v", err)
+	}
+
+	if _, err := gorm.G[VersionedAccount](DB).Where("id = ?", account.ID).Updates(ctx, VersionedAccount{Name: "generics", Version: result.Version}); err != nil {
+		t.Errorf("failed to update with generics, got error %v", err)
+	}
+
+	if DB.First(&result, account.ID); result.Version.Int64 != 6 || result.Name != "generics" {
+		t.Errorf("version should be increased by generics updates, got %+v", result)
+	}
+}
+
+func TestVersionFullSaveAssociations(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedAccount{}, &VersionedPayment{})
+	if err := DB.AutoMigrate(&VersionedAccount{}, &VersionedPayment{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	account := VersionedAccount{Name: "associations", Payments: []VersionedPayment{{Amount: 10}, {Amount: 20}}}
+	if err := DB.Create(&account).Error; err != nil || account.Payments[0].Version.Int64 != 1 || account.Payments[1].Version.Int64 != 1 {
+		t.Fatalf("failed to create associations, got %+v, error %v", account.Payments, err)
+	}
+
+	var stale VersionedAccount
+	DB.Preload("Payments").First(&stale, account.ID)
+
+	account.Payments[0].Amount = 11
+	if err := DB.Session(&gorm.Session{FullSaveAssociations: true}).Save(&account).Error; err != nil {
+		t.Fatalf("failed to save associations, got error %v", err)
+	}
+
+	if account.Version.Int64 != 2 || account.Payments[0].Version.Int64 != 2 || account.Payments[1].Version.Int64 != 2 {
+		t.Errorf("versions of associations should be increased, got %+v", account)
+	}
+
+	var payment VersionedPayment
+	if DB.First(&payment, account.Payments[0].ID); payment.Amount != 11 || payment.Version.Int64 != 2 {
+		t.Errorf("association should be saved, got %+v", payment)
+	}
+
+	stale.Version = account.Version
+	stale.Payments[0].Amount = 12
+	if err := DB.Session(&gorm.Session{FullSaveAssociations: true}).Save(&stale).Error; !errors.Is(err, gorm.ErrOptimisticLockConflict) {
+		t.Errorf("should returns optimistic lock conflict error for stale associations, got %v", err)
+	}
+
+	if DB.First(&payment, account.Payments[0].ID); payment.Amount != 11 || payment.Version.Int64 != 2 {
+		t.Errorf("stale association should not be saved, got %+v", payment)
+	}
+}
+
+func TestVersionBulkUpdates(t *testing.T) {
+	DB.Migrator().DropTable(&VersionedAccount{}, &VersionedPayment{})
+	if err := DB.AutoMigrate(&VersionedAccount{}, &VersionedPayment{}); err != nil {
+		t.Fatalf("failed to migrate, got error %v", err)
+	}
+
+	accounts := []VersionedAccount{{Name: "bulk1", Balance: 20}, {Name: "bulk2", Balance: 20}, {Name: "bulk3", Balance: 30}}
+	DB.Create(&accounts)
+	for i := 0; i < 2; i++ {
+		if err := DB.Model(&accounts[0]).Update("name", "bulk1").Error; err != nil {
+			t.Fatalf("failed to update, got error %v", err)
+		}
+	}
+
+	if err := DB.Model(&VersionedAccount{}).Where("balance = ?", 20).Updates(VersionedAccount{Name: "bulk"}).Error; err != nil {
+		t.Fatalf("failed to update in bulk, got error %v", err)
+	}
+
+	var results []VersionedAccount
+	DB.Order("id").Find(&results)
+	for idx, version := range []int64{4, 2, 1} {
+		if results[idx].Version.Int64 != version {
+			t.Errorf("version of %v should be %v after bulk updates, got %+v", results[idx].Name, version, results[idx].Version)
+		}
+	}
+
+	if err := DB.Model(&accounts[1]).Update("balance", 50).Error; !errors.Is(err, gorm.ErrOptimisticLockConflict) {
+		t.Errorf("should returns optimistic lock conflict error for records changed by bulk updates, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package tests_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm/clause"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	users := []User{
+		*GetUser("window_1", Config{}),
+		*GetUser("window_2", Config{}),
+		*GetUser("window_3", Config{}),
+		*GetUser("window_4", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age, users[3].Age = 10, 20, 20, 40
+	users[0].Active, users[1].Active, users[2].Active, users[3].Active = true, false, true, false
+	DB.Create(&users)
+
+	type result struct {
+		Name    string
+		RowNum  int
+		Ranking int
+		Prev    uint
+		Total   uint
+	}
+
+	byAge := clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}}, {Column: clause.Column{Name: "name"}}}}
+	var results []result
+	if err := DB.Model(&User{}).Select("name, ? AS row_num, ? AS ranking, ? AS prev, ? AS total",
+		clause.RowNumber(clause.Window{PartitionBy: []clause.Column{{Name: "active"}}, OrderBy: byAge}),
+		clause.Rank(clause.Window{OrderBy: clause.OrderBy{Columns: []clause.OrderByColumn{{Column: clause.Column{Name: "age"}}}}}),
+		clause.Lag(clause.Column{Name: "age"}, 1, clause.Window{OrderBy: byAge}, 0),
+		clause.Sum(clause.Column{Name: "age"}, clause.Window{OrderBy: byAge, Frame: &clause.Frame{
+			Start: clause.FrameBound{Type: clause.FrameUnboundedPreceding},
+			End:   &clause.FrameBound{Type: clause.FrameCurrentRow},
+		}}),
+	).Where("name LIKE ?", "window_%").Order("name").Scan(&results).Error; err != nil {
+		t.Fatalf("failed to query window functions, got error %v", err)
+	}
+
+	expects := []result{
+		{Name: "window_1", RowNum: 1, Ranking: 1, Prev: 0, Total: 10},
+		{Name: "window_2", RowNum: 1, Ranking: 2, Prev: 10, Total: 30},
+		{Name: "window_3", RowNum: 2, Ranking: 2, Prev: 20, Total: 50},
+		{Name: "window_4", RowNum: 2, Ranking: 4, Prev: 20, Total: 90},
+	}
+
+	if len(results) != len(expects) {
+		t.Fatalf("failed to query window functions, got %+v", results)
+	}
+
+	for idx, expect := range expects {
+		if results[idx] != expect {
+			t.Errorf("failed to query window functions, expects %+v, got %+v", expect, results[idx])
+		}
+	}
+
+	var names []string
+	if err := DB.Model(&User{}).Where("name LIKE ?", "window_%").
+		Order(clause.Lead(clause.Column{Name: "age"}, 1, clause.Window{OrderBy: byAge}, 100)).
+		Pluck("name", &names).Error; err != nil {
+		t.Fatalf("failed to order by window function, got error %v", err)
+	}
+
+	// lead ages: window_1 => 20, window_2 => 20, window_3 => 40, window_4 => 100
+	if len(names) != 4 || names[2] != "window_3" || names[3] != "window_4" {
+		t.Errorf("failed to order by window function, got %v", names)
+	}
+}
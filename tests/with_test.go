@@ -0,0 +1,85 @@
+package tests_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"gorm.io/gorm"
+	. "gorm.io/gorm/utils/tests"
+)
+
+func TestWith(t *testing.T) {
+	users := []User{
+		*GetUser("with_1", Config{}),
+		*GetUser("with_2", Config{}),
+		*GetUser("with_3", Config{}),
+	}
+	users[0].Age, users[1].Age, users[2].Age = 10, 20, 30
+	DB.Create(&users)
+
+	var results []User
+	if err := DB.With("with_users", DB.Model(&User{}).Where("name LIKE ? AND age > ?", "with_%", 15)).
+		Table("with_users").Order("age").Find(&results).Error; err != nil {
+		t.Fatalf("failed to query with cte, got error %v", err)
+	}
+
+	if len(results) != 2 || results[0].Name != "with_2" || results[1].Name != "with_3" {
+		t.Errorf("failed to query with cte, got %+v", results)
+	}
+
+	var names []string
+	if err := DB.With("with_names", DB.Model(&User{}).Select("id", "name").Where("name LIKE ?", "with_%"), "user_id", "user_name").
+		Table("with_names").Order("user_name").Pluck("user_name", &names).Error; err != nil {
+		t.Fatalf("failed to query with cte columns, got error %v", err)
+	}
+
+	if len(names) != 3 || names[0] != "with_1" || names[2] != "with_3" {
+		t.Errorf("failed to query with cte columns, got %v", names)
+	}
+
+	var count int64
+	if err := DB.Model(&User{}).With("with_old", DB.Model(&User{}).Select("id").Where("name LIKE ? AND age >= ?", "with_%", 20)).
+		Where("id IN (SELECT id FROM with_old)").Count(&count).Error; err != nil || count != 2 {
+		t.Errorf("failed to count with cte, got count %v, error %v", count, err)
+	}
+
+	result := DB.Model(&User{}).With("with_young", DB.Model(&User{}).Select("id").Where("name LIKE ? AND age < ?", "with_%", 15)).
+		Where("id IN (SELECT id FROM with_young)").Update("age", 11)
+	if result.Error != nil || result.RowsAffected != 1 {
+		t.Errorf("failed to update with cte, got rows affected %v, error %v", result.RowsAffected, result.Error)
+	}
+
+	generics, err := gorm.G[User](DB).With("with_users", DB.Model(&User{}).Where("name LIKE ?", "with_%")).
+		Table("with_users").Where("age = ?", 11).Find(context.Background())
+	if err != nil || len(generics) != 1 || generics[0].Name != "with_1" {
+		t.Errorf("failed to query with cte through generics, got %+v, error %v", generics, err)
+	}
+}
+
+func TestWithRecursive(t *testing.T) {
+	root := *GetUser("with_recursive_root", Config{})
+	DB.Create(&root)
+
+	child := *GetUser("with_recursive_child", Config{})
+	child.ManagerID = &root.ID
+	DB.Create(&child)
+
+	grandchild := *GetUser("with_recursive_grandchild", Config{})
+	grandchild.ManagerID = &child.ID
+	DB.Create(&grandchild)
+
+	DB.Create(GetUser("with_recursive_other", Config{}))
+
+	tree := DB.Raw("SELECT id, name FROM users WHERE id = ? UNION ALL SELECT u.id, u.name FROM users u JOIN tree ON u.manager_id = tree.id", root.ID)
+
+	var names []string
+	if err := DB.WithRecursive("tree", tree, "id", "name").Table("tree").Pluck("name", &names).Error; err != nil {
+		t.Fatalf("failed to query with recursive cte, got error %v", err)
+	}
+
+	sort.Strings(names)
+	if len(names) != 3 || names[0] != "with_recursive_child" || names[1] != "with_recursive_grandchild" || names[2] != "with_recursive_root" {
+		t.Errorf("failed to query with recursive cte, got %v", names)
+	}
+}
@@ -0,0 +1,43 @@
+package gorm
+
+import "context"
+
+type contextTxKey struct{}
+
+// ContextWithTx returns a context carrying the transaction tx, sessions of the same database created with the context
+// join the transaction if JoinContextTransaction is enabled
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//	  return repo.CreateUser(gorm.ContextWithTx(ctx, tx), &user)
+//	})
+//
+//	func (repo *Repo) CreateUser(ctx context.Context, user *User) error {
+//	  return repo.db.WithContext(ctx).Create(user).Error
+//	}
+func ContextWithTx(ctx context.Context, tx *DB) context.Context {
+	return context.WithValue(ctx, contextTxKey{}, tx)
+}
+
+// TxFromContext returns the transaction carried by ctx
+func TxFromContext(ctx context.Context) (*DB, bool) {
+	tx, ok := ctx.Value(contextTxKey{}).(*DB)
+	return tx, ok && tx != nil
+}
+
+// joinContextTransaction uses the connection of the transaction carried by the context of db, if db is not in a
+// transaction, nested Transaction calls use savepoints of the joined transaction then, or run in it directly if
+// DisableNestedTransaction is enabled
+func (db *DB) joinContextTransaction() {
+	tx, ok := TxFromContext(db.Statement.Context)
+	if !ok || tx.Statement == nil || tx.cacheStore != db.cacheStore {
+		return
+	}
+
+	if committer, ok := tx.Statement.ConnPool.(TxCommitter); !ok || committer == nil {
+		return
+	}
+
+	if _, ok := db.Statement.ConnPool.(TxCommitter); !ok {
+		db.Statement.ConnPool = tx.Statement.ConnPool
+	}
+}
@@ -0,0 +1,157 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+type transactionHooksKey struct {
+	tx interface{}
+}
+
+// transactionHooks hooks of a transaction and its savepoints, hooks registered after the savepoint savePoints[i] is
+// created are discarded when rolling back to it
+type transactionHooks struct {
+	mu            sync.Mutex
+	savePoints    []string
+	afterCommit   []transactionHook
+	afterRollback []transactionHook
+}
+
+type transactionHook struct {
+	savePoints int
+	fc         func(ctx context.Context)
+}
+
+func (hooks *transactionHooks) add(afterCommit bool, fc func(ctx context.Context)) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	hook := transactionHook{savePoints: len(hooks.savePoints), fc: fc}
+	if afterCommit {
+		hooks.afterCommit = append(hooks.afterCommit, hook)
+	} else {
+		hooks.afterRollback = append(hooks.afterRollback, hook)
+	}
+}
+
+func (hooks *transactionHooks) savePoint(name string) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.savePoints = append(hooks.savePoints, name)
+}
+
+func (hooks *transactionHooks) rollbackTo(name string) {
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+
+	// savepoints not found are created before hooks are registered
+	idx := len(hooks.savePoints) - 1
+	for ; idx >= 0 && hooks.savePoints[idx] != name; idx-- {
+	}
+
+	discard := func(hooks []transactionHook) []transactionHook {
+		kept := hooks[:0]
+		for _, hook := range hooks {
+			if hook.savePoints <= idx {
+				kept = append(kept, hook)
+			}
+		}
+		return kept
+	}
+
+	// the savepoint is kept after rolling back to it
+	hooks.savePoints = hooks.savePoints[:idx+1]
+	hooks.afterCommit = discard(hooks.afterCommit)
+	hooks.afterRollback = discard(hooks.afterRollback)
+}
+
+func (hooks *transactionHooks) run(ctx context.Context, committed bool) {
+	hooks.mu.Lock()
+	runs := hooks.afterRollback
+	if committed {
+		runs = hooks.afterCommit
+	}
+	hooks.mu.Unlock()
+
+	for _, hook := range runs {
+		hook.fc(ctx)
+	}
+}
+
+// transactionHooksKey returns the key of hooks of the transaction of db, inTransaction is false if db is not in a
+// transaction
+func (db *DB) transactionHooksKey() (key transactionHooksKey, inTransaction bool, err error) {
+	var tx interface{} = db.Statement.ConnPool
+	if preparedStmtTx, isPreparedStmtTx := tx.(*PreparedStmtTX); isPreparedStmtTx {
+		tx = preparedStmtTx.Tx
+	}
+
+	if committer, isCommitter := tx.(TxCommitter); !isCommitter || committer == nil {
+		return key, false, nil
+	} else if !reflect.TypeOf(tx).Comparable() {
+		return key, true, fmt.Errorf("%w: hooks of transactions of uncomparable type %T are unsupported", ErrInvalidTransaction, tx)
+	}
+	return transactionHooksKey{tx: tx}, true, nil
+}
+
+// transactionHooks returns hooks of the transaction of db, returns nil if db is not in a transaction or no hooks are
+// registered unless create is true, hooks are stored until the transaction is committed or rolled back with gorm
+func (db *DB) transactionHooks(create bool) (*transactionHooks, error) {
+	key, inTransaction, err := db.transactionHooksKey()
+	if !inTransaction || err != nil || db.transactionHooksStore == nil {
+		return nil, err
+	}
+
+	if v, ok := db.transactionHooksStore.Load(key); ok {
+		return v.(*transactionHooks), nil
+	} else if create {
+		v, _ = db.transactionHooksStore.LoadOrStore(key, &transactionHooks{})
+		return v.(*transactionHooks), nil
+	}
+	return nil, nil
+}
+
+// completeTransaction runs hooks of the completed transaction of db
+func (db *DB) completeTransaction(committed bool) {
+	if key, inTransaction, err := db.transactionHooksKey(); inTransaction && err == nil && db.transactionHooksStore != nil {
+		if v, loaded := db.transactionHooksStore.LoadAndDelete(key); loaded {
+			v.(*transactionHooks).run(db.Statement.Context, committed)
+		}
+	}
+}
+
+// AfterCommit registers fc to be called after the outermost transaction of db is committed, fc is called immediately
+// if db is not in a transaction, and it is discarded if the transaction or the savepoint it's registered in rolls back,
+// an error is added to db if its transaction can't have hooks
+//
+//	db.Transaction(func(tx *gorm.DB) error {
+//	  tx.Create(&user)
+//	  tx.AfterCommit(func(ctx context.Context) {
+//	    cache.Delete(ctx, user.ID)
+//	  })
+//	  return nil
+//	})
+func (db *DB) AfterCommit(fc func(ctx context.Context)) *DB {
+	if hooks, err := db.transactionHooks(true); err != nil {
+		db.AddError(err)
+	} else if hooks != nil {
+		hooks.add(true, fc)
+	} else {
+		fc(db.Statement.Context)
+	}
+	return db
+}
+
+// AfterRollback registers fc to be called after the outermost transaction of db is rolled back, fc is discarded if db
+// is not in a transaction or the savepoint it's registered in rolls back
+func (db *DB) AfterRollback(fc func(ctx context.Context)) *DB {
+	if hooks, err := db.transactionHooks(true); err != nil {
+		db.AddError(err)
+	} else if hooks != nil {
+		hooks.add(false, fc)
+	}
+	return db
+}
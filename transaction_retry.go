@@ -0,0 +1,104 @@
+package gorm
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy retry policy of transactions, transactions failing with retryable errors are started over up to
+// MaxAttempts attempts, waiting Backoff before the second attempt, doubled after each attempt up to MaxBackoff
+//
+//	db.Session(&gorm.Session{TransactionRetry: &gorm.RetryPolicy{MaxAttempts: 3, Backoff: 10 * time.Millisecond}}).
+//	  Transaction(func(tx *gorm.DB) error {
+//	    // may be called again if it failed with a serialization failure or a deadlock
+//	  })
+type RetryPolicy struct {
+	// MaxAttempts max attempts of a transaction, including the first one
+	MaxAttempts int
+	// Backoff wait duration before the second attempt
+	Backoff time.Duration
+	// MaxBackoff max wait duration between attempts, unlimited if zero
+	MaxBackoff time.Duration
+	// Jitter random fraction of wait durations added to them, between 0 and 1
+	Jitter float64
+	// Retryable classifies errors of transactions, errors translated to ErrSerializationFailure or ErrDeadlock by the
+	// dialector, and driver errors of SQLSTATE 40001 or 40P01 reported by their SQLState methods like errors of pgx
+	// and lib/pq are retryable by default, wrapped and joined errors are classified as well
+	Retryable func(err error) bool
+}
+
+func (policy *RetryPolicy) retryable(db *DB, err error) bool {
+	if policy.Retryable != nil {
+		return policy.Retryable(err)
+	}
+
+	if isRetryableError(err) {
+		return true
+	}
+
+	translator, _ := db.Dialector.(ErrorTranslator)
+	return anyError(err, func(err error) bool {
+		if translator != nil && isRetryableError(translator.Translate(err)) {
+			return true
+		}
+
+		e, ok := err.(interface{ SQLState() string })
+		return ok && retryableSQLStates[e.SQLState()]
+	})
+}
+
+func isRetryableError(err error) bool {
+	return errors.Is(err, ErrSerializationFailure) || errors.Is(err, ErrDeadlock)
+}
+
+// retryableSQLStates serialization failures and deadlocks
+var retryableSQLStates = map[string]bool{"40001": true, "40P01": true}
+
+// anyError returns true if fc returns true for err or any error wrapped by it, including errors joined together
+func anyError(err error, fc func(error) bool) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if fc(err) {
+			return true
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, err := range joined.Unwrap() {
+				if anyError(err, fc) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// wait waits before the next attempt of attempt, returns the error of ctx if it is done
+func (policy *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	backoff := policy.Backoff
+	for i := 1; i < attempt && (policy.MaxBackoff <= 0 || backoff < policy.MaxBackoff); i++ {
+		backoff *= 2
+	}
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	if policy.Jitter > 0 {
+		backoff += time.Duration(rand.Float64() * policy.Jitter * float64(backoff))
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
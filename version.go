@@ -0,0 +1,282 @@
+package gorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"reflect"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Version optimistic lock version, updates of records having versions are conditioned on their current versions and
+// increase them, ErrOptimisticLockConflict is returned if the record has been changed by others
+//
+//	type User struct {
+//	  ID      uint
+//	  Name    string
+//	  Version gorm.Version
+//	}
+//
+//	db.Model(&user).Update("name", "jinzhu") // user.Version is 3
+//	// UPDATE `users` SET `name`="jinzhu",`version`=4 WHERE `users`.`version` = 3 AND `id` = 1
+//
+// Records created without versions start at version 1, omit the version field to update without the lock, upserts
+// of Save and FullSaveAssociations are locked with ON CONFLICT ... WHERE, which is not supported by MySQL
+type Version sql.NullInt64
+
+// Scan implements the Scanner interface.
+func (v *Version) Scan(value interface{}) error {
+	return (*sql.NullInt64)(v).Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (v Version) Value() (driver.Value, error) {
+	if !v.Valid {
+		return nil, nil
+	}
+	return v.Int64, nil
+}
+
+func (v Version) MarshalJSON() ([]byte, error) {
+	if v.Valid {
+		return json.Marshal(v.Int64)
+	}
+	return json.Marshal(nil)
+}
+
+func (v *Version) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		v.Valid = false
+		return nil
+	}
+	err := json.Unmarshal(b, &v.Int64)
+	if err == nil {
+		v.Valid = true
+	}
+	return err
+}
+
+// VersionLock optimistic lock of a statement, it conflicts if less than Rows rows are affected, versions changed by
+// the statement are restored if it conflicts or fails, Assignments are added to SET clauses of updates
+type VersionLock struct {
+	Rows        int64
+	Assignments []clause.Assignment
+	restores    []func()
+}
+
+// Build implements clause.Expression interface
+func (VersionLock) Build(clause.Builder) {
+}
+
+// Check checks the lock after the statement is executed
+func (lock VersionLock) Check(db *DB) {
+	if db.Error != nil || db.DryRun || db.RowsAffected == 0 || db.RowsAffected < lock.Rows {
+		for _, restore := range lock.restores {
+			restore()
+		}
+
+		if db.Error == nil && !db.DryRun && db.RowsAffected < lock.Rows {
+			db.AddError(ErrOptimisticLockConflict)
+		}
+	}
+}
+
+func (lock *VersionLock) set(stmt *Statement, field *schema.Field, value reflect.Value, version Version) {
+	current, _ := field.ValueOf(stmt.Context, value)
+	if stmt.AddError(field.Set(stmt.Context, value, version)) == nil {
+		lock.restores = append(lock.restores, func() {
+			field.Set(stmt.Context, value, current)
+		})
+	}
+}
+
+func currentVersion(stmt *Statement, field *schema.Field, value reflect.Value) (version Version) {
+	if value.Kind() == reflect.Struct {
+		if v, _ := field.ValueOf(stmt.Context, value); v != nil {
+			version, _ = v.(Version)
+		}
+	}
+	return version
+}
+
+func (Version) UpdateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionUpdateClause{Field: f}}
+}
+
+type VersionUpdateClause struct {
+	Field *schema.Field
+}
+
+func (v VersionUpdateClause) Name() string {
+	return ""
+}
+
+func (v VersionUpdateClause) Build(clause.Builder) {
+}
+
+func (v VersionUpdateClause) MergeClause(*clause.Clause) {
+}
+
+func (v VersionUpdateClause) ModifyStatement(stmt *Statement) {
+	if _, ok := stmt.Clauses["version_lock"]; ok || stmt.SQL.Len() > 0 {
+		return
+	}
+
+	selectColumns, restricted := stmt.SelectAndOmitColumns(false, true)
+	if selected, ok := selectColumns[v.Field.DBName]; ok && !selected {
+		return
+	}
+
+	var (
+		lock    VersionLock
+		current = currentVersion(stmt, v.Field, stmt.ReflectValue)
+		next    = Version{Int64: current.Int64 + 1, Valid: true}
+		// versions of records updated without current versions are increased by the database
+		increase = clause.Expr{SQL: "COALESCE(?, 0) + 1", Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: v.Field.DBName}}}
+	)
+
+	switch dest := stmt.Dest.(type) {
+	case map[string]interface{}:
+		// copy updating values to not change them
+		values := make(map[string]interface{}, len(dest)+1)
+		for key, value := range dest {
+			if key != v.Field.Name && key != v.Field.DBName {
+				values[key] = value
+			}
+		}
+
+		if current.Valid {
+			values[v.Field.DBName] = next
+		} else {
+			values[v.Field.DBName] = increase
+		}
+		stmt.Dest = values
+
+		if current.Valid && stmt.ReflectValue.CanAddr() {
+			lock.set(stmt, v.Field, stmt.ReflectValue, next)
+		}
+	case []map[string]interface{}:
+		return
+	default:
+		destValue := reflect.ValueOf(stmt.Dest)
+		for destValue.Kind() == reflect.Ptr {
+			destValue = destValue.Elem()
+		}
+
+		if destValue.Kind() != reflect.Struct {
+			return
+		}
+
+		if !current.Valid {
+			// versions of structs are omitted as they could be versions of other records
+			stmt.Omits = append(stmt.Omits[:len(stmt.Omits):len(stmt.Omits)], v.Field.DBName)
+			lock.Assignments = []clause.Assignment{{Column: clause.Column{Name: v.Field.DBName}, Value: increase}}
+			break
+		}
+
+		if !destValue.CanAddr() {
+			addressable := reflect.New(destValue.Type())
+			addressable.Elem().Set(destValue)
+			stmt.Dest = addressable.Interface()
+			destValue = addressable.Elem()
+		}
+
+		field := v.Field
+		if destValue.Type() != stmt.Schema.ModelType {
+			updatingStmt := &Statement{DB: stmt.DB}
+			if err := updatingStmt.Parse(stmt.Dest); err != nil {
+				return
+			} else if field = updatingStmt.Schema.LookUpField(v.Field.DBName); field == nil {
+				return
+			}
+		}
+		lock.set(stmt, field, destValue, next)
+
+		if stmt.ReflectValue.Kind() == reflect.Struct && stmt.ReflectValue.CanAddr() && stmt.ReflectValue != destValue {
+			lock.set(stmt, v.Field, stmt.ReflectValue, next)
+		}
+
+		if restricted && !selectColumns[v.Field.DBName] {
+			stmt.Selects = append(stmt.Selects[:len(stmt.Selects):len(stmt.Selects)], v.Field.DBName)
+		}
+	}
+
+	if current.Valid {
+		wrapOrConditions(stmt)
+		stmt.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: v.Field.DBName}, Value: current},
+		}})
+		lock.Rows = 1
+	}
+	stmt.Clauses["version_lock"] = clause.Clause{Expression: lock}
+}
+
+func (Version) CreateClauses(f *schema.Field) []clause.Interface {
+	return []clause.Interface{VersionCreateClause{Field: f}}
+}
+
+type VersionCreateClause struct {
+	Field *schema.Field
+}
+
+func (v VersionCreateClause) Name() string {
+	return ""
+}
+
+func (v VersionCreateClause) Build(clause.Builder) {
+}
+
+func (v VersionCreateClause) MergeClause(*clause.Clause) {
+}
+
+func (v VersionCreateClause) ModifyStatement(stmt *Statement) {
+	if _, ok := stmt.Clauses["version_lock"]; ok || stmt.SQL.Len() > 0 {
+		return
+	}
+
+	selectColumns, _ := stmt.SelectAndOmitColumns(true, false)
+	if selected, ok := selectColumns[v.Field.DBName]; ok && !selected {
+		return
+	}
+
+	// upserts updating all columns are conditioned on the current versions of conflicting rows
+	onConflict, upsert := stmt.Clauses["ON CONFLICT"].Expression.(clause.OnConflict)
+	upsert = upsert && onConflict.UpdateAll && !onConflict.DoNothing
+
+	var lock VersionLock
+	setVersion := func(value reflect.Value) {
+		value = reflect.Indirect(value)
+		if value.Kind() != reflect.Struct || !value.CanAddr() {
+			return
+		}
+
+		if current := currentVersion(stmt, v.Field, value); !current.Valid {
+			lock.set(stmt, v.Field, value, Version{Int64: 1, Valid: true})
+		} else if upsert {
+			lock.set(stmt, v.Field, value, Version{Int64: current.Int64 + 1, Valid: true})
+		}
+		lock.Rows++
+	}
+
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			setVersion(stmt.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		setVersion(stmt.ReflectValue)
+	}
+
+	if !upsert {
+		lock.Rows = 0
+	} else {
+		onConflict.Where.Exprs = append(onConflict.Where.Exprs, clause.Expr{
+			SQL:  "? = ? - 1",
+			Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: v.Field.DBName}, clause.Column{Table: "excluded", Name: v.Field.DBName}},
+		})
+		stmt.AddClause(onConflict)
+	}
+	stmt.Clauses["version_lock"] = clause.Clause{Expression: lock}
+}